@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
 
 	"github.com/dorochadev/aka/launcher"
@@ -12,7 +11,7 @@ import (
 )
 
 var addCmd = &cobra.Command{
-	Use:   "add <shortname> <target>",
+	Use:   "add <shortname> <target> | --action <name>=<target>...",
 	Short: "Create a new launcher",
 	Long: `Create a new launcher for an application, URL, SSH connection, or command.
 
@@ -21,9 +20,21 @@ The target can be:
   - Application name (e.g., "Safari", "VS Code")
   - URL (e.g., https://youtube.com)
   - SSH connection (e.g., user@host)
-  - Shell command (e.g., "ls -la")`,
-	Args: cobra.MinimumNArgs(2),
-	RunE: runAdd,
+  - Shell command (e.g., "ls -la")
+  - Directory to jump to, with --type dir (e.g., ~/code/proj)
+
+Instead of a target, give named actions with --action to get one launcher
+with sub-commands: 'proj build', 'proj test'. Run without an action, it
+lists them.
+
+The type is detected from the target unless --type is given. Directory
+launchers change your shell's directory when loaded with 'aka shell-init';
+run as a file they open a new shell there.`,
+	Example: `  aka add safari Safari
+  aka add proj --action build="go build ./..." --action test="go test ./..."`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{annotationStructuredOutput: "true", annotationDryRun: "true"},
+	RunE:        runAdd,
 }
 
 func init() {
@@ -33,6 +44,12 @@ func init() {
 	addCmd.Flags().StringToString("env", nil, "Environment variables (key=value)")
 	addCmd.Flags().IntP("port", "", 22, "SSH port")
 	addCmd.Flags().StringP("key", "k", "", "SSH key file path")
+	addCmd.Flags().Bool("adopt", false, "Take over an existing file that aka did not create")
+	addCmd.Flags().StringSliceP("tag", "t", nil, "Tag the launcher (repeatable or comma-separated)")
+	addCmd.Flags().StringP("desc", "d", "", "Short description of the launcher")
+	addCmd.Flags().String("type", "", "Launcher type: app, url, ssh, cmd or dir (detected if omitted)")
+	addCmd.Flags().StringArrayP("action", "a", nil, "Add a named action as name=target (repeatable)")
+	addCmd.Flags().Bool("allow-shadow", false, "Use a name that is already a command on PATH or a shell builtin")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -40,6 +57,20 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	// Collect all remaining args as potential targets
 	rawTargets := args[1:]
 
+	actions, err := parseActions(cmd)
+	if err != nil {
+		ui.PrintError(err.Error())
+		return withCode(codeInvalidArgument, err)
+	}
+	switch {
+	case len(actions) > 0 && len(rawTargets) > 0:
+		ui.PrintError("Give either a target or --action, not both.")
+		return withCode(codeInvalidArgument, fmt.Errorf("target and actions given"))
+	case len(actions) == 0 && len(rawTargets) == 0:
+		ui.PrintError("Give a target, or define actions with --action name=target.")
+		return withCode(codeInvalidArgument, fmt.Errorf("no target given"))
+	}
+
 	var targets []string
 	if len(rawTargets) == 1 && strings.Contains(rawTargets[0], ",") {
 		// Handle comma-separated list: aka add dev "VS Code,Safari"
@@ -55,38 +86,51 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	// Determine if it's a stack or single launcher
 	isStack := len(targets) > 1
 	var target string
-	if !isStack {
+	if len(targets) == 1 {
 		target = targets[0]
 	}
 
 	if !isValidShortname(shortname) {
 		ui.PrintError("Invalid shortname. Use only alphanumeric characters, hyphens, and underscores.")
-		return fmt.Errorf("invalid shortname")
+		return withCode(codeInvalidArgument, fmt.Errorf("invalid shortname"))
 	}
 
-	if launcher.Exists(shortname) {
-		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			overwrite := ui.Confirm(fmt.Sprintf("Launcher '%s' already exists. Overwrite?", shortname))
-			if !overwrite {
-				ui.PrintInfo("Cancelled.")
-				return nil
-			}
-		}
+	if err := checkShadowing(cmd, shortname); err != nil {
+		return err
 	}
 
 	var launcherType launcher.LauncherType
-	if isStack {
+	typeFlag, _ := cmd.Flags().GetString("type")
+	switch {
+	case len(actions) > 0:
+		launcherType = launcher.TypeActions
+	case isStack:
 		launcherType = launcher.TypeStack
-	} else {
+	case typeFlag != "":
+		launcherType = launcher.LauncherType(typeFlag)
+		switch launcherType {
+		case launcher.TypeApplication, launcher.TypeURL, launcher.TypeSSH, launcher.TypeCommand, launcher.TypeDirectory:
+		default:
+			ui.PrintError(fmt.Sprintf("Unknown type '%s'. Use app, url, ssh, cmd or dir.", typeFlag))
+			return withCode(codeInvalidArgument, fmt.Errorf("unknown type"))
+		}
+	default:
 		launcherType = launcher.DetectLauncherType(target)
 	}
 
 	metadata := &launcher.LauncherMetadata{
-		Type:    launcherType,
-		Target:  target,
-		Targets: targets,
+		Type:   launcherType,
+		Target: target,
 	}
+	if isStack {
+		metadata.Targets = targets
+	}
+	metadata.Actions = actions
+
+	description, _ := cmd.Flags().GetString("desc")
+	metadata.Description = strings.TrimSpace(description)
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	metadata.Tags = launcher.NormalizeTags(tags)
 
 	envVars, _ := cmd.Flags().GetStringToString("env")
 	if len(envVars) > 0 {
@@ -103,7 +147,10 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			KeyFile: keyFile,
 		}
 
-		if savePassword {
+		if savePassword && dryRun(cmd) {
+			// Stand in for the password so the preview uses sshpass
+			metadata.SSHConfig.Password = "********"
+		} else if savePassword {
 			password, err := ui.PromptPassword(fmt.Sprintf("🔒 Enter SSH password for %s (will be stored securely): ", target))
 			if err != nil {
 				ui.PrintError(fmt.Sprintf("Failed to read password: %v", err))
@@ -113,11 +160,57 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Aliases are names for the launcher rather than part of its definition,
+	// so redefining it keeps them
+	if existing, _ := launcher.GetMetadata(shortname); existing != nil {
+		metadata.Aliases = existing.Aliases
+	}
+
+	if err := launcher.Validate(shortname, metadata); err != nil {
+		ui.PrintError(err.Error())
+		return withCode(codeInvalidArgument, err)
+	}
+
+	if launcher.Exists(shortname) {
+		if err := adoptIfRequested(cmd, shortname); err != nil {
+			return err
+		}
+	}
+
+	if dryRun(cmd) {
+		changes, err := launcher.PreviewCreate(shortname, metadata)
+		if err != nil {
+			ui.PrintError(err.Error())
+			return err
+		}
+		return printFileChanges(changes)
+	}
+
+	if launcher.Exists(shortname) {
+		force, _ := cmd.Flags().GetBool("force")
+		if !force && structuredOutput() {
+			ui.PrintError(fmt.Sprintf("Launcher '%s' already exists. Use --force to overwrite it.", shortname))
+			return withCode(codeConflict, fmt.Errorf("launcher exists"))
+		}
+		if !force && !confirmOverwrite(shortname, metadata) {
+			ui.PrintInfo("Cancelled.")
+			return nil
+		}
+	}
+
 	if err := launcher.Create(shortname, metadata); err != nil {
 		ui.PrintError(fmt.Sprintf("Failed to create launcher: %v", err))
 		return err
 	}
 
+	if structuredOutput() {
+		store, err := launcher.LoadMetadata()
+		if err != nil {
+			return err
+		}
+		return writeRecord(newLauncherRecord(shortname, target, store[shortname], launcher.Usage{}), launcherTSV)
+	}
+
 	fmt.Println()
 
 	switch launcherType {
@@ -127,6 +220,14 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			ui.PrintResult("-", t)
 		}
 		ui.PrintExample("Launch all:", shortname)
+	case launcher.TypeActions:
+		names := launcher.ActionNames(metadata)
+		ui.SuccessBox(fmt.Sprintf("Created launcher '%s' with %d actions", shortname, len(names)))
+		for _, name := range names {
+			ui.PrintResult(name, actions[name])
+		}
+		ui.PrintExample("Run an action:", shortname+" "+names[0])
+		ui.PrintExample("List the actions:", shortname)
 	case launcher.TypeURL:
 		ui.SuccessBox(fmt.Sprintf("Created URL launcher '%s' for %s", shortname, target))
 		ui.PrintExample("Open the URL:", shortname)
@@ -136,6 +237,10 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	case launcher.TypeCommand:
 		ui.SuccessBox(fmt.Sprintf("Created command launcher '%s'", shortname))
 		ui.PrintExample("Run the command:", shortname)
+	case launcher.TypeDirectory:
+		ui.SuccessBox(fmt.Sprintf("Created directory launcher '%s' for %s", shortname, target))
+		ui.PrintExample("Jump there:", shortname)
+		ui.PrintInfo(`To change directory in place, load launchers as functions: eval "$(aka shell-init)"`)
 	default:
 		ui.SuccessBox(fmt.Sprintf("Created launcher '%s' for %s", shortname, target))
 		ui.PrintExample("Open the application:", shortname)
@@ -156,6 +261,40 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// parseActions reads the --action name=target flags
+func parseActions(cmd *cobra.Command) (map[string]string, error) {
+	values, _ := cmd.Flags().GetStringArray("action")
+	if len(values) == 0 {
+		return nil, nil
+	}
+	actions := make(map[string]string, len(values))
+	for _, v := range values {
+		name, target, ok := strings.Cut(v, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid action '%s': use name=target", v)
+		}
+		actions[name] = strings.TrimSpace(target)
+	}
+	return actions, nil
+}
+
+// confirmOverwrite shows how an existing launcher's script would change and
+// asks whether to go ahead
+func confirmOverwrite(name string, metadata *launcher.LauncherMetadata) bool {
+	changes, err := launcher.PreviewCreate(name, metadata)
+	if err == nil {
+		for _, c := range changes {
+			if c.Diff != "" {
+				fmt.Println()
+				ui.Diff(c.Diff)
+				fmt.Println()
+			}
+		}
+	}
+	return ui.Confirm(fmt.Sprintf("Launcher '%s' already exists. Overwrite?", name))
+}
+
 func printReloadInstructions() {
 	shell := os.Getenv("SHELL")
 
@@ -176,7 +315,47 @@ func printReloadInstructions() {
 	fmt.Println()
 }
 
+// adoptIfRequested refuses to touch a file aka did not create unless --adopt
+// was given, in which case the file is recorded as managed
+func adoptIfRequested(cmd *cobra.Command, name string) error {
+	if launcher.IsManaged(name) {
+		return nil
+	}
+
+	adopt, _ := cmd.Flags().GetBool("adopt")
+	if !adopt {
+		ui.PrintError(fmt.Sprintf("'%s' in %s was not created by aka. Use --adopt to manage it anyway.", name, launcher.GetLauncherDir()))
+		return launcher.ErrNotManaged
+	}
+	if dryRun(cmd) {
+		return nil
+	}
+
+	if err := launcher.Adopt(name); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to adopt '%s': %v", name, err))
+		return err
+	}
+	return nil
+}
+
+// checkShadowing refuses a name that is already a shell builtin or a command
+// on PATH unless --allow-shadow was given, and says which one would run
+func checkShadowing(cmd *cobra.Command, name string) error {
+	shadow := launcher.FindShadow(name)
+	if shadow == nil {
+		return nil
+	}
+
+	if allow, _ := cmd.Flags().GetBool("allow-shadow"); allow {
+		ui.PrintWarning(fmt.Sprintf("'%s' has the %s", name, shadow.Detail()))
+		return nil
+	}
+
+	ui.PrintError(fmt.Sprintf("'%s' has the %s.", name, shadow.Detail()))
+	ui.PrintInfo("Pick another name, or pass --allow-shadow to use it anyway.")
+	return withCode(codeConflict, fmt.Errorf("name shadows an existing command"))
+}
+
 func isValidShortname(name string) bool {
-	matched, _ := regexp.MatchString(`^[a-zA-Z0-9_-]+$`, name)
-	return matched
+	return launcher.ValidName(name)
 }
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Give launchers extra names",
+	Long: `Let one launcher answer to several names. Each alias is a link to the
+launcher's script, so it shares the definition and never drifts apart.
+Renaming a launcher moves its aliases along; removing it removes them too.`,
+}
+
+var aliasAddCmd = &cobra.Command{
+	Use:     "add <shortname> <alias>...",
+	Short:   "Add aliases to a launcher",
+	Example: `  aka alias add gh github gith`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    runAliasAdd,
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:     "remove <alias>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove aliases",
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    runAliasRemove,
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List launchers that have aliases",
+	Args:    cobra.NoArgs,
+	RunE:    runAliasList,
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasAddCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasAddCmd.Flags().Bool("allow-shadow", false, "Use a name that is already a command on PATH or a shell builtin")
+}
+
+func runAliasAdd(cmd *cobra.Command, args []string) error {
+	name, aliases := args[0], args[1:]
+
+	for _, alias := range aliases {
+		if !isValidShortname(alias) {
+			ui.PrintError(fmt.Sprintf("Invalid alias '%s'. Use only alphanumeric characters, hyphens, and underscores.", alias))
+			return withCode(codeInvalidArgument, fmt.Errorf("invalid alias"))
+		}
+		if err := checkShadowing(cmd, alias); err != nil {
+			return err
+		}
+	}
+
+	if err := launcher.AddAliases(name, aliases); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to add aliases to '%s': %v", name, err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("'%s' now also answers to %s", name, strings.Join(aliases, ", ")))
+	fmt.Println()
+	return nil
+}
+
+func runAliasRemove(cmd *cobra.Command, args []string) error {
+	for _, alias := range args {
+		owner, err := launcher.RemoveAlias(alias)
+		if err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to remove alias '%s': %v", alias, err))
+			return err
+		}
+		ui.PrintSuccess(fmt.Sprintf("Removed alias '%s' of '%s'", alias, owner))
+	}
+	return nil
+}
+
+func runAliasList(cmd *cobra.Command, args []string) error {
+	store, err := launcher.LoadMetadata()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+
+	var rows [][]string
+	for name, meta := range store {
+		if meta != nil && len(meta.Aliases) > 0 {
+			rows = append(rows, []string{name, strings.Join(meta.Aliases, ", ")})
+		}
+	}
+	if len(rows) == 0 {
+		ui.PrintInfo("No launchers have aliases.")
+		return nil
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+
+	fmt.Println()
+	ui.Table([]string{"Launcher", "Aliases"}, rows)
+	fmt.Println()
+	return nil
+}
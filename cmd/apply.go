@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply [file]",
+	Short: "Create, update and remove launchers to match a config file",
+	Long: `Make the launchers match a declarative config file (default: aka.yaml).
+
+Launchers in the file are created or updated, and launchers that are not in
+the file are removed unless --no-delete is given. The plan is shown before
+anything changes. Generate a starting file with 'aka export --format yaml'.
+
+SSH passwords are never stored in the file; passwords saved on this machine
+are kept when a launcher is updated.`,
+	Example: `  aka export --format yaml -o aka.yaml
+  aka apply --dry-run
+  aka apply aka.yaml`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{annotationDryRun: "true"},
+	RunE:        runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().BoolP("force", "f", false, "Apply without confirmation")
+	applyCmd.Flags().Bool("no-delete", false, "Keep launchers that are not in the file")
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	path := "aka.yaml"
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	spec, err := launcher.LoadSpec(path)
+	if err != nil {
+		ui.PrintError(err.Error())
+		return err
+	}
+
+	noDelete, _ := cmd.Flags().GetBool("no-delete")
+	changes, err := launcher.PlanApply(spec, !noDelete)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to plan changes: %v", err))
+		return err
+	}
+
+	pending := printPlan(changes)
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if pending == 0 || dryRun {
+		return nil
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force && !ui.Confirm("Apply these changes?") {
+		ui.PrintInfo("Cancelled.")
+		return nil
+	}
+
+	for _, change := range changes {
+		if err := change.Apply(); err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to %s '%s': %v", change.Action, change.Name, err))
+			return err
+		}
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Applied %d change(s)", pending))
+	fmt.Println()
+
+	return nil
+}
+
+// printPlan shows planned changes terraform-style and returns how many
+// launchers would change
+func printPlan(changes []launcher.Change) int {
+	counts := make(map[launcher.ChangeAction]int)
+
+	fmt.Println()
+	for _, change := range changes {
+		counts[change.Action]++
+		switch change.Action {
+		case launcher.ActionCreate:
+			ui.CurrentTheme.Success.Printf("  + %s\n", change.Name)
+			for _, field := range change.Fields() {
+				ui.CurrentTheme.Muted.Printf("      %s\n", field)
+			}
+		case launcher.ActionUpdate:
+			ui.CurrentTheme.Warning.Printf("  ~ %s\n", change.Name)
+			for _, field := range change.Fields() {
+				ui.CurrentTheme.Muted.Printf("      %s\n", field)
+			}
+		case launcher.ActionDelete:
+			ui.CurrentTheme.Error.Printf("  - %s\n", change.Name)
+		}
+	}
+
+	pending := counts[launcher.ActionCreate] + counts[launcher.ActionUpdate] + counts[launcher.ActionDelete]
+	if pending > 0 {
+		fmt.Println()
+	}
+	ui.PrintInfo(fmt.Sprintf("Plan: %d to create, %d to update, %d to delete, %d unchanged.",
+		counts[launcher.ActionCreate], counts[launcher.ActionUpdate], counts[launcher.ActionDelete], counts[launcher.ActionUnchanged]))
+	fmt.Println()
+
+	return pending
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check launchers for drift and missing dependencies",
+	Long: `Compare the launcher directory with launchers.json and report:
+
+  - scripts without metadata
+  - metadata without scripts
+  - scripts that differ from what aka would generate today
+  - launchers whose runtime dependencies are missing
+
+With --fix, each fixable problem is offered for repair: outdated scripts are
+regenerated, scripts without metadata are adopted and stale metadata is pruned.
+
+Scripts embed their own metadata, so --reindex can restore launchers.json from
+the launcher directory alone, for example after copying scripts from another
+machine.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationStructuredOutput: "true"},
+	RunE:        runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().Bool("fix", false, "Offer to repair each fixable problem")
+	doctorCmd.Flags().BoolP("force", "f", false, "Apply fixes without confirmation")
+	doctorCmd.Flags().Bool("reindex", false, "Restore launchers.json entries from metadata embedded in scripts")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	if reindex, _ := cmd.Flags().GetBool("reindex"); reindex {
+		added, err := launcher.RebuildIndex()
+		if err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to rebuild index: %v", err))
+			return err
+		}
+		if !structuredOutput() {
+			fmt.Println()
+			ui.SuccessBox(fmt.Sprintf("Restored %d launcher(s) from their scripts", len(added)))
+			if len(added) > 0 {
+				ui.List(added)
+			}
+		}
+	}
+
+	issues, err := launcher.Diagnose()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to check launchers: %v", err))
+		return err
+	}
+
+	if structuredOutput() {
+		return writeIssues(cmd, issues)
+	}
+
+	fmt.Println()
+	if len(issues) == 0 {
+		ui.SuccessBox("No problems found")
+		fmt.Println()
+		return nil
+	}
+
+	headers := []string{"Launcher", "Problem", "Detail"}
+	rows := make([][]string, len(issues))
+	for i, issue := range issues {
+		rows[i] = []string{issue.Name, string(issue.Kind), issue.Detail}
+	}
+	ui.Table(headers, rows)
+	fmt.Println()
+	ui.PrintWarning(fmt.Sprintf("Found %d problem(s)", len(issues)))
+
+	fix, _ := cmd.Flags().GetBool("fix")
+	if !fix {
+		ui.PrintExample("Repair what can be repaired:", "aka doctor --fix")
+		fmt.Println()
+		return nil
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	fixed, failed := 0, 0
+	fmt.Println()
+	for _, issue := range issues {
+		if !issue.Fixable() {
+			continue
+		}
+		if !force && !ui.Confirm(issue.FixDescription()+"?") {
+			continue
+		}
+		if err := issue.Fix(); err != nil {
+			ui.PrintError(fmt.Sprintf("%s: %v", issue.Name, err))
+			failed++
+			continue
+		}
+		fixed++
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Fixed %d problem(s)", fixed))
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d fix(es) failed", failed)
+	}
+	return nil
+}
+
+// issueRecord is the structured form of a problem found by doctor
+type issueRecord struct {
+	Name    string `json:"name" yaml:"name"`
+	Kind    string `json:"kind" yaml:"kind"`
+	Detail  string `json:"detail" yaml:"detail"`
+	Fixable bool   `json:"fixable" yaml:"fixable"`
+	Fixed   bool   `json:"fixed" yaml:"fixed"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// writeIssues reports issues as records, fixing them first with --fix.
+// Nobody can answer prompts here, so fixing requires --force.
+func writeIssues(cmd *cobra.Command, issues []launcher.Issue) error {
+	fix, _ := cmd.Flags().GetBool("fix")
+	force, _ := cmd.Flags().GetBool("force")
+	if fix && !force {
+		ui.PrintError("Fixing problems needs confirmation. Use --fix --force.")
+		return withCode(codeConfirmationRequired, fmt.Errorf("confirmation required"))
+	}
+
+	records := make([]issueRecord, len(issues))
+	for i, issue := range issues {
+		r := issueRecord{Name: issue.Name, Kind: string(issue.Kind), Detail: issue.Detail, Fixable: issue.Fixable()}
+		if fix && r.Fixable {
+			if err := issue.Fix(); err != nil {
+				r.Error = err.Error()
+			} else {
+				r.Fixed = true
+			}
+		}
+		records[i] = r
+	}
+
+	return writeRecords(records, func(r issueRecord) []string {
+		return []string{r.Name, r.Kind, r.Detail}
+	})
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+// annotationDryRun marks commands that honour the global --dry-run flag
+const annotationDryRun = "dry-run"
+
+// dryRun reports whether the command should only show what it would change
+func dryRun(cmd *cobra.Command) bool {
+	enabled, _ := cmd.Flags().GetBool("dry-run")
+	return enabled
+}
+
+// checkDryRun rejects --dry-run on commands that would ignore it
+func checkDryRun(cmd *cobra.Command) error {
+	if dryRun(cmd) && cmd.Annotations[annotationDryRun] != "true" {
+		return withCode(codeUnsupported, fmt.Errorf("'%s' does not support --dry-run", cmd.CommandPath()))
+	}
+	return nil
+}
+
+type fileChangeRecord struct {
+	Op      string `json:"op" yaml:"op"`
+	Path    string `json:"path" yaml:"path"`
+	NewPath string `json:"new_path,omitempty" yaml:"new_path,omitempty"`
+	Diff    string `json:"diff,omitempty" yaml:"diff,omitempty"`
+}
+
+func fileChangeTSV(r fileChangeRecord) []string {
+	return []string{r.Op, r.Path, r.NewPath}
+}
+
+// printFileChanges reports the files a dry run would touch, with script diffs
+func printFileChanges(changes []launcher.FileChange) error {
+	if structuredOutput() {
+		records := make([]fileChangeRecord, 0, len(changes))
+		for _, c := range changes {
+			records = append(records, fileChangeRecord{Op: string(c.Op), Path: c.Path, NewPath: c.NewPath, Diff: c.Diff})
+		}
+		return writeRecords(records, fileChangeTSV)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println()
+		ui.PrintInfo("Dry run: nothing would change.")
+		fmt.Println()
+		return nil
+	}
+
+	ui.Section("Would change")
+	for _, c := range changes {
+		switch c.Op {
+		case launcher.FileRename:
+			ui.PrintResult(string(c.Op), fmt.Sprintf("%s -> %s", c.Path, c.NewPath))
+		default:
+			ui.PrintResult(string(c.Op), c.Path)
+		}
+	}
+	for _, c := range changes {
+		if c.Diff != "" {
+			fmt.Println()
+			ui.Diff(c.Diff)
+		}
+	}
+	fmt.Println()
+	ui.PrintInfo("Dry run: no changes written.")
+	fmt.Println()
+	return nil
+}
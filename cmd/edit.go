@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <shortname>",
+	Short: "Edit a launcher in your editor",
+	Long: `Open a launcher's definition as YAML in $VISUAL or $EDITOR. When you save
+and close, the definition is validated; if it has problems the editor opens
+again with the errors written in as comments. A valid definition regenerates
+the script.
+
+With --script, edit the generated script itself. The result is kept as a
+hand-edited override: 'aka rebuild' and automatic rebuilds leave it alone
+until you run 'aka rebuild --discard-edits'.`,
+	Example: `  aka edit prod
+  EDITOR="code --wait" aka edit prod
+  aka edit prod --script`,
+	Args: cobra.ExactArgs(1),
+	RunE: runEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+	editCmd.Flags().Bool("script", false, "Edit the script itself instead of the definition")
+	editCmd.Flags().BoolP("force", "f", false, "Replace a hand-edited script without confirmation")
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	store, err := launcher.LoadMetadata()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+	current := store[name]
+	if current == nil {
+		if launcher.Exists(name) {
+			ui.PrintError(fmt.Sprintf("'%s' has no recorded definition. Run 'aka doctor --fix' first.", name))
+		} else {
+			ui.PrintError(fmt.Sprintf("Launcher '%s' does not exist", name))
+		}
+		return fmt.Errorf("launcher not found")
+	}
+
+	if script, _ := cmd.Flags().GetBool("script"); script {
+		return editScript(name)
+	}
+
+	original, err := launcher.EditDocument(name, current)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to prepare %s for editing: %v", name, err))
+		return err
+	}
+
+	var next *launcher.LauncherMetadata
+	doc := original
+	for {
+		edited, err := editInEditor(name+".yaml", doc)
+		if err != nil {
+			ui.PrintError(fmt.Sprintf("Editor failed: %v", err))
+			return err
+		}
+		edited = launcher.StripEditErrors(edited)
+
+		if len(bytes.TrimSpace(edited)) == 0 {
+			ui.PrintInfo("Cancelled.")
+			return nil
+		}
+		if bytes.Equal(edited, original) {
+			ui.PrintInfo("No changes.")
+			return nil
+		}
+
+		next, err = launcher.ParseEditDocument(name, edited, current)
+		if err == nil {
+			break
+		}
+		ui.PrintError(err.Error())
+		doc = launcher.AnnotateEditError(edited, err)
+	}
+
+	changes := launcher.FieldChanges(current, next)
+	if len(changes) == 0 {
+		ui.PrintInfo("No changes.")
+		return nil
+	}
+
+	if edited, _ := launcher.ScriptIsHandEdited(name); edited {
+		force, _ := cmd.Flags().GetBool("force")
+		if !force && !ui.Confirm(fmt.Sprintf("The script of '%s' was edited by hand. Replace it?", name)) {
+			ui.PrintInfo("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := launcher.Create(name, next); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to update launcher: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Updated launcher '%s'", name))
+	ui.List(changes)
+	fmt.Println()
+
+	return nil
+}
+
+// editScript lets the user rewrite a launcher's script directly
+func editScript(name string) error {
+	path := launcher.ScriptPath(name)
+	original, err := os.ReadFile(path)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to read %s: %v", path, err))
+		return err
+	}
+
+	edited, err := editInEditor(name+".sh", original)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Editor failed: %v", err))
+		return err
+	}
+	if bytes.Equal(edited, original) {
+		ui.PrintInfo("No changes.")
+		return nil
+	}
+	if len(bytes.TrimSpace(edited)) == 0 {
+		ui.PrintInfo("Cancelled.")
+		return nil
+	}
+
+	if err := launcher.WriteEditedScript(name, string(edited)); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to write script: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Saved the script of '%s'", name))
+	ui.PrintInfo("Rebuilds will leave it alone. To go back to the generated script:")
+	ui.PrintCommand("aka rebuild " + name + " --discard-edits")
+	fmt.Println()
+
+	return nil
+}
+
+// editInEditor writes content to a temporary file, opens it in the user's
+// editor and returns what was saved
+func editInEditor(filename string, content []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "aka-edit-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, filename)
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		return nil, err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if strings.TrimSpace(editor) == "" {
+		editor = "vi"
+	}
+
+	// Run through the shell so editors with arguments, like "code --wait", work
+	proc := exec.Command("sh", "-c", editor+` "$1"`, "sh", path)
+	proc.Stdin = os.Stdin
+	proc.Stdout = os.Stdout
+	proc.Stderr = os.Stderr
+	if err := proc.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w", editor, err)
+	}
+
+	return os.ReadFile(path)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [shortname...]",
+	Short: "Export launcher definitions",
+	Long: `Write launcher definitions to stdout or a file, either all of them or
+only the named ones.
+
+  yaml  a declarative config that 'aka apply' understands (default)
+  json  a bundle for 'aka import', to hand launchers to a teammate
+
+When --format is not given, a -o file ending in .json selects json. Secrets
+such as SSH passwords are never exported; 'aka import' asks for them again.`,
+	Example: `  aka export --format yaml -o aka.yaml
+  aka export gh prod -o bundle.json
+  aka export --tag infra -o infra.json`,
+	Annotations: map[string]string{annotationMachineOutput: "true"},
+	RunE:        runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().String("format", "", "Output format (yaml|json)")
+	exportCmd.Flags().StringP("file", "o", "", "Write to a file instead of stdout")
+	exportCmd.Flags().StringSliceP("tag", "t", nil, "Export launchers with all of these tags")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	output, _ := cmd.Flags().GetString("file")
+	format, _ := cmd.Flags().GetString("format")
+	if format == "" {
+		format = "yaml"
+		if filepath.Ext(output) == ".json" {
+			format = "json"
+		}
+	}
+
+	store, err := launcher.LoadMetadata()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	selected, err := selectLaunchers(store, args, tags)
+	if err != nil {
+		ui.PrintError(err.Error())
+		return err
+	}
+
+	var data []byte
+	switch format {
+	case "yaml":
+		data, err = launcher.ExportSpec(selected)
+	case "json":
+		data, err = launcher.NewBundle(selected).Marshal()
+	default:
+		ui.PrintError(fmt.Sprintf("Unknown format: %s", format))
+		return fmt.Errorf("unknown format")
+	}
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to export: %v", err))
+		return err
+	}
+
+	if output == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to write %s: %v", output, err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Exported %d launcher(s) to %s", len(selected), output))
+	fmt.Println()
+
+	return nil
+}
+
+// selectLaunchers narrows the store to the named launchers plus those carrying
+// all of tags, or returns it unchanged when neither is given
+func selectLaunchers(store launcher.MetadataStore, names, tags []string) (launcher.MetadataStore, error) {
+	if len(names) == 0 && len(tags) == 0 {
+		return store, nil
+	}
+
+	selected := make(launcher.MetadataStore, len(names))
+	if len(tags) > 0 {
+		for _, name := range taggedLaunchers(store, tags) {
+			selected[name] = store[name]
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("no launchers are tagged %s", strings.Join(launcher.NormalizeTags(tags), ", "))
+		}
+	}
+	for _, name := range names {
+		meta, ok := store[name]
+		if !ok || meta == nil {
+			return nil, fmt.Errorf("launcher '%s' does not exist", name)
+		}
+		selected[name] = meta
+	}
+	return selected, nil
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var helpCmd = &cobra.Command{
+	Use:   "help [command | launcher]",
+	Short: "Show help for a command or a launcher",
+	Long: `Show help for an aka command, or describe a launcher: what it runs, how
+to call it and examples. Every launcher script prints the same description
+when run with --aka-help:
+
+  proj --aka-help
+
+Use --flag to answer to another argument if --aka-help clashes with
+something, for instance a command that passes its arguments on.`,
+	Example: `  aka help add
+  aka help proj
+  aka help --flag --launcher-help`,
+	RunE: runHelp,
+}
+
+func init() {
+	rootCmd.SetHelpCommand(helpCmd)
+	helpCmd.Flags().String("flag", "", fmt.Sprintf("Argument launcher scripts print their help for (default %s)", launcher.DefaultHelpFlag))
+}
+
+func runHelp(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("flag") {
+		flag, _ := cmd.Flags().GetString("flag")
+		return runHelpFlag(flag)
+	}
+
+	if len(args) == 0 {
+		return rootCmd.Help()
+	}
+
+	// aka's own commands come first; launchers cannot be named after them
+	if target, _, err := rootCmd.Find(args); err == nil && target != rootCmd {
+		return target.Help()
+	}
+
+	if len(args) > 1 {
+		ui.PrintError(fmt.Sprintf("Unknown command '%s'", strings.Join(args, " ")))
+		return withCode(codeNotFound, fmt.Errorf("unknown help topic"))
+	}
+	name := args[0]
+	if owner := launcher.AliasOwner(name); owner != "" {
+		name = owner
+	}
+	if !launcher.Exists(name) {
+		ui.PrintError(fmt.Sprintf("'%s' is neither an aka command nor a launcher", args[0]))
+		fmt.Println()
+		ui.PrintExample("See all commands:", "aka help")
+		return withCode(codeNotFound, fmt.Errorf("unknown help topic"))
+	}
+
+	meta, err := launcher.GetMetadata(name)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+	if meta == nil {
+		ui.PrintError(fmt.Sprintf("'%s' has no definition to describe", name))
+		ui.PrintExample("Inspect its script instead:", "aka show "+name)
+		return withCode(codeNotFound, fmt.Errorf("launcher has no metadata"))
+	}
+
+	printLauncherHelp(args[0], launcher.Help(meta))
+	return nil
+}
+
+// printLauncherHelp is the themed form of a launcher's help text, as called
+// by name
+func printLauncherHelp(name string, h launcher.LauncherHelp) {
+	fmt.Println()
+	ui.Header(name, h.Description)
+	ui.CurrentTheme.Body.Println(h.Summary)
+
+	fmt.Println()
+	ui.CurrentTheme.Primary.Println("USAGE")
+	usage := name
+	if h.Usage != "" {
+		usage += " " + h.Usage
+	}
+	fmt.Print("  ")
+	ui.CurrentTheme.Body.Println(usage)
+
+	fmt.Println()
+	ui.CurrentTheme.Primary.Println("DETAILS")
+	ui.KeyValue("Type", string(h.Type))
+	for _, d := range h.Details {
+		ui.KeyValue(d.Label, d.Value)
+	}
+
+	if len(h.Actions) > 0 {
+		fmt.Println()
+		ui.CurrentTheme.Primary.Println("ACTIONS")
+		for _, a := range h.Actions {
+			ui.CurrentTheme.Accent.Printf("  %-12s", a.Label)
+			ui.CurrentTheme.Body.Printf("%s\n", a.Value)
+		}
+	}
+
+	fmt.Println()
+	ui.CurrentTheme.Primary.Println("EXAMPLES")
+	for _, e := range h.Examples {
+		if e == "" {
+			ui.PrintCommand(name)
+		} else {
+			ui.PrintCommand(name + " " + e)
+		}
+	}
+	fmt.Println()
+}
+
+func runHelpFlag(flag string) error {
+	if flag == "" {
+		flag = launcher.DefaultHelpFlag
+	}
+	if err := launcher.SetHelpFlag(flag); err != nil {
+		ui.PrintError(err.Error())
+		return withCode(codeInvalidArgument, err)
+	}
+
+	rebuilt, err := launcher.RebuildOutdated()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to update launchers: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	ui.PrintSuccess(fmt.Sprintf("Launchers now print their help for %s", flag))
+	if len(rebuilt) > 0 {
+		ui.PrintInfo(fmt.Sprintf("Updated %d launcher(s)", len(rebuilt)))
+	}
+	ui.PrintInfo("Scripts edited by hand keep the old flag until 'aka rebuild --discard-edits'.")
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [shortname]",
+	Short: "Show recent changes to your launchers",
+	Long: `Show the changes recorded for your launchers, newest first, or only those
+for one launcher. Each change has an ID; pass it to --revert to set the
+launcher back to how it was right after that change.`,
+	Example: `  aka history
+  aka history prod
+  aka history prod --revert 12`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().IntP("limit", "n", 20, "Number of changes to show (0 for all)")
+	historyCmd.Flags().Int("revert", 0, "Set the launcher back to its definition after this change")
+	historyCmd.Flags().BoolP("force", "f", false, "Revert without confirmation")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("revert") {
+		if len(args) == 0 {
+			ui.PrintError("Name the launcher to revert: aka history <shortname> --revert <id>")
+			return fmt.Errorf("launcher name required")
+		}
+		id, _ := cmd.Flags().GetInt("revert")
+		return revertLauncher(cmd, args[0], id)
+	}
+
+	var entries []launcher.HistoryEntry
+	var err error
+	if len(args) == 1 {
+		entries, err = launcher.LauncherHistory(args[0])
+	} else {
+		entries, err = launcher.LoadHistory()
+	}
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to read history: %v", err))
+		return err
+	}
+
+	if len(entries) == 0 {
+		ui.PrintInfo("No changes recorded yet.")
+		return nil
+	}
+
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	var rows [][]string
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		rows = append(rows, []string{
+			strconv.Itoa(e.ID),
+			ui.Ago(e.Time),
+			string(e.Op),
+			e.Name,
+			truncate(e.Summary(), 60),
+		})
+	}
+
+	fmt.Println()
+	ui.Table([]string{"ID", "When", "Change", "Launcher", "Details"}, rows)
+	fmt.Println()
+	return nil
+}
+
+// revertLauncher sets a launcher back to its definition after history entry id
+func revertLauncher(cmd *cobra.Command, name string, id int) error {
+	current, err := launcher.GetMetadata(name)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force {
+		prompt := fmt.Sprintf("Revert '%s' to how it was after change #%d?", name, id)
+		if current == nil {
+			prompt = fmt.Sprintf("Recreate '%s' as it was after change #%d?", name, id)
+		}
+		if !ui.Confirm(prompt) {
+			ui.PrintInfo("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := launcher.RevertTo(name, id); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to revert '%s': %v", name, err))
+		return err
+	}
+
+	after, _ := launcher.GetMetadata(name)
+	changes := launcher.FieldChanges(current, after)
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Reverted '%s' to change #%d", name, id))
+	if len(changes) > 0 {
+		ui.List(changes)
+	} else {
+		ui.PrintInfo(fmt.Sprintf("'%s' already matched that definition.", name))
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Import launchers from a bundle",
+	Long: `Create launchers from a bundle written by 'aka export' (json) or from an
+aka.yaml file.
+
+The bundle is validated before anything is created. When a launcher with the
+same name already exists, --on-conflict decides what happens:
+
+  skip       keep the existing launcher (default)
+  overwrite  replace it with the imported one
+  rename     import under a free name such as gh-2
+  ask        decide for each conflict
+
+SSH launchers that had a saved password before export prompt for it again.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationDryRun: "true"},
+	RunE:        runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().String("on-conflict", "skip", "What to do with existing names (skip|overwrite|rename|ask)")
+	importCmd.Flags().Bool("no-prompt", false, "Do not ask for stripped SSH passwords")
+}
+
+type importResult struct {
+	name   string
+	source string
+	action string
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	onConflict, _ := cmd.Flags().GetString("on-conflict")
+	switch onConflict {
+	case "skip", "overwrite", "rename", "ask":
+	default:
+		ui.PrintError(fmt.Sprintf("Unknown --on-conflict value: %s", onConflict))
+		return fmt.Errorf("invalid --on-conflict")
+	}
+
+	bundle, err := launcher.LoadBundle(args[0])
+	if err != nil {
+		ui.PrintError(err.Error())
+		return err
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noPrompt, _ := cmd.Flags().GetBool("no-prompt")
+
+	var results []importResult
+	for _, source := range bundle.Names() {
+		meta := bundle.Launchers[source]
+		name := source
+		action := "created"
+
+		if launcher.Exists(name) || launcherHasMetadata(name) {
+			choice := onConflict
+			if choice == "ask" {
+				choice = askConflict(name)
+			}
+			switch choice {
+			case "overwrite":
+				if !launcher.IsManaged(name) {
+					results = append(results, importResult{name, source, "skipped (not managed by aka)"})
+					continue
+				}
+				action = "overwritten"
+			case "rename":
+				name = launcher.AvailableName(source)
+				action = "renamed"
+			default:
+				results = append(results, importResult{name, source, "skipped"})
+				continue
+			}
+		}
+
+		if dryRun {
+			results = append(results, importResult{name, source, "would be " + action})
+			continue
+		}
+
+		if bundle.NeedsPassword(source) && !noPrompt {
+			password, err := ui.PromptPassword(fmt.Sprintf("🔒 Enter SSH password for %s (%s): ", name, meta.Target))
+			if err != nil {
+				ui.PrintError(fmt.Sprintf("Failed to read password: %v", err))
+				return err
+			}
+			if password != "" {
+				if meta.SSHConfig == nil {
+					meta.SSHConfig = &launcher.SSHConfig{}
+				}
+				meta.SSHConfig.Password = password
+			}
+		}
+
+		if err := launcher.Create(name, meta); err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to import '%s': %v", source, err))
+			return err
+		}
+		results = append(results, importResult{name, source, action})
+	}
+
+	printImportResults(results, dryRun)
+	return nil
+}
+
+func launcherHasMetadata(name string) bool {
+	meta, _ := launcher.GetMetadata(name)
+	return meta != nil
+}
+
+// askConflict prompts for what to do with one existing launcher
+func askConflict(name string) string {
+	for {
+		answer := strings.ToLower(ui.PromptDefault(fmt.Sprintf("Launcher '%s' already exists. [s]kip, [o]verwrite or [r]ename?", name), "s"))
+		switch answer {
+		case "s", "skip":
+			return "skip"
+		case "o", "overwrite":
+			return "overwrite"
+		case "r", "rename":
+			return "rename"
+		}
+	}
+}
+
+func printImportResults(results []importResult, dryRun bool) {
+	fmt.Println()
+	if len(results) == 0 {
+		ui.PrintInfo("The bundle is empty.")
+		fmt.Println()
+		return
+	}
+
+	imported := 0
+	rows := make([][]string, len(results))
+	for i, r := range results {
+		action := r.action
+		if r.action == "renamed" {
+			action = "created (renamed from " + r.source + ")"
+		}
+		if r.action == "created" || r.action == "overwritten" || r.action == "renamed" {
+			imported++
+		}
+		rows[i] = []string{r.name, action}
+	}
+
+	ui.Table([]string{"Launcher", "Result"}, rows)
+	fmt.Println()
+	if dryRun {
+		ui.PrintInfo("Dry run: nothing was imported.")
+	} else {
+		ui.SuccessBox(fmt.Sprintf("Imported %d of %d launcher(s)", imported, len(results)))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var importAliasesCmd = &cobra.Command{
+	Use:   "aliases",
+	Short: "Turn shell aliases and functions into launchers",
+	Long: `Read alias and function definitions from your shell rc files and propose
+a command launcher for each one.
+
+By default ~/.bashrc, ~/.bash_aliases, ~/.bash_profile, ~/.zshrc and
+~/.config/fish/config.fish are read. Definitions that depend on the state of
+the running shell (cd, export, source, ...) cannot work as standalone scripts
+and are skipped.
+
+With --comment-out, imported definitions are commented out in the rc file
+afterwards; a backup is written next to it first.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationDryRun: "true"},
+	RunE:        runImportAliases,
+}
+
+func init() {
+	importCmd.AddCommand(importAliasesCmd)
+	importAliasesCmd.Flags().StringSlice("file", nil, "rc file to read (repeatable)")
+	importAliasesCmd.Flags().BoolP("force", "f", false, "Create launchers without confirmation")
+	importAliasesCmd.Flags().Bool("comment-out", false, "Comment out imported definitions in the rc file")
+}
+
+func runImportAliases(cmd *cobra.Command, args []string) error {
+	files, _ := cmd.Flags().GetStringSlice("file")
+	if len(files) == 0 {
+		files = launcher.DefaultRCFiles()
+	}
+	if len(files) == 0 {
+		ui.PrintError("No shell rc files found. Use --file to point at one.")
+		return fmt.Errorf("no rc files")
+	}
+
+	var defs []launcher.ShellDefinition
+	for _, file := range files {
+		found, err := launcher.ParseRCFile(file)
+		if err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to read %s: %v", file, err))
+			return err
+		}
+		defs = append(defs, found...)
+	}
+
+	var proposals []launcher.ShellDefinition
+	var rows [][]string
+	seen := make(map[string]bool)
+	for _, def := range defs {
+		status := "import"
+		switch {
+		case seen[def.Name]:
+			status = "skip: defined more than once"
+		case !isValidShortname(def.Name):
+			status = "skip: not a valid launcher name"
+		case len(def.StateDependent) > 0:
+			status = "skip: " + strings.Join(def.StateDependent, ", ")
+		case launcher.Exists(def.Name):
+			status = "skip: already exists"
+		default:
+			proposals = append(proposals, def)
+		}
+		seen[def.Name] = true
+
+		preview := strings.ReplaceAll(def.Body, "\n", "; ")
+		if len(preview) > 40 {
+			preview = preview[:37] + "..."
+		}
+		rows = append(rows, []string{def.Name, def.Kind, preview, status})
+	}
+
+	fmt.Println()
+	if len(rows) == 0 {
+		ui.PrintInfo("No aliases or functions found.")
+		fmt.Println()
+		return nil
+	}
+	ui.Table([]string{"Name", "Kind", "Definition", "Proposal"}, rows)
+	fmt.Println()
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if len(proposals) == 0 || dryRun {
+		ui.PrintInfo(fmt.Sprintf("%d of %d definition(s) can become launchers.", len(proposals), len(defs)))
+		fmt.Println()
+		return nil
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force && !ui.Confirm(fmt.Sprintf("Create %d command launcher(s)?", len(proposals))) {
+		ui.PrintInfo("Cancelled.")
+		return nil
+	}
+
+	var imported []launcher.ShellDefinition
+	for _, def := range proposals {
+		metadata := &launcher.LauncherMetadata{
+			Type:   launcher.TypeCommand,
+			Target: def.Command(),
+		}
+		if err := launcher.Create(def.Name, metadata); err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to create '%s': %v", def.Name, err))
+			continue
+		}
+		imported = append(imported, def)
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Created %d launcher(s)", len(imported)))
+	fmt.Println()
+
+	commentOut, _ := cmd.Flags().GetBool("comment-out")
+	if !commentOut || len(imported) == 0 {
+		return nil
+	}
+
+	byFile := make(map[string][]launcher.ShellDefinition)
+	for _, def := range imported {
+		byFile[def.File] = append(byFile[def.File], def)
+	}
+	for _, file := range files {
+		fileDefs := byFile[file]
+		if len(fileDefs) == 0 {
+			continue
+		}
+		if !force && !ui.Confirm(fmt.Sprintf("Comment out %d definition(s) in %s?", len(fileDefs), file)) {
+			continue
+		}
+		backup, err := launcher.CommentOutDefinitions(file, fileDefs)
+		if err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to update %s: %v", file, err))
+			return err
+		}
+		ui.PrintInfo(fmt.Sprintf("Updated %s (backup: %s)", file, backup))
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/dorochadev/aka/launcher"
 	"github.com/dorochadev/aka/ui"
@@ -13,22 +15,49 @@ var listCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "List all launchers",
-	Long:    `Display all configured launchers and their target applications.`,
-	RunE:    runList,
+	Long: `Display all configured launchers and their target applications.
+
+Narrow the list with --tag, --type and --search, and order it with --sort:
+
+  name       alphabetical (default)
+  type       grouped by launcher type
+  created    newest first
+  last-used  most recently run first`,
+	Example: `  aka list --tag infra --type ssh --search prod
+  aka list --sort last-used`,
+	Annotations: map[string]string{annotationStructuredOutput: "true"},
+	RunE:        runList,
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().Bool("foreign", false, "Show files in the launcher directory that aka does not manage")
+	listCmd.Flags().StringSliceP("tag", "t", nil, "Only launchers with all of these tags")
+	listCmd.Flags().String("type", "", "Only launchers of this type (app, url, ssh, cmd, dir, stack, actions)")
+	listCmd.Flags().StringP("search", "s", "", "Only launchers whose name, aliases, target, description or tags contain this text")
+	listCmd.Flags().String("sort", "name", "Sort by name, type, created or last-used")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if foreign, _ := cmd.Flags().GetBool("foreign"); foreign {
+		return runListForeign()
+	}
+
+	sortBy, _ := cmd.Flags().GetString("sort")
+	switch sortBy {
+	case "name", "type", "created", "last-used":
+	default:
+		ui.PrintError(fmt.Sprintf("Unknown sort order '%s'. Use name, type, created or last-used.", sortBy))
+		return withCode(codeInvalidArgument, fmt.Errorf("unknown sort order"))
+	}
+
 	launchers, err := launcher.List()
 	if err != nil {
 		ui.PrintError(fmt.Sprintf("Failed to list launchers: %v", err))
 		return err
 	}
 
-	if len(launchers) == 0 {
+	if len(launchers) == 0 && !structuredOutput() {
 		fmt.Println()
 		ui.PrintInfo("No launchers configured yet.")
 		fmt.Println()
@@ -37,34 +66,231 @@ func runList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	metadata, _ := launcher.LoadMetadata()
+	usage, err := launcher.LoadUsage()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to read usage log: %v", err))
+		return err
+	}
+
+	total := len(launchers)
+	launchers = filterLaunchers(cmd, launchers)
+	sortLaunchers(launchers, sortBy, usage)
+
+	if structuredOutput() {
+		records := make([]launcherRecord, len(launchers))
+		for i, l := range launchers {
+			records[i] = newLauncherRecord(l.Name, l.Target, l.Metadata, usage[l.Name])
+		}
+		return writeRecords(records, launcherTSV)
+	}
+
+	if len(launchers) == 0 {
+		fmt.Println()
+		ui.PrintInfo(fmt.Sprintf("No launchers match (of %d).", total))
+		fmt.Println()
+		return nil
+	}
+
+	showTags, showDesc := false, false
+	for _, l := range launchers {
+		if l.Metadata != nil {
+			showTags = showTags || len(l.Metadata.Tags) > 0
+			showDesc = showDesc || l.Metadata.Description != ""
+		}
+	}
 
 	headers := []string{"Command", "Type", ui.IconArrow, "Target"}
-	rows := make([][]string, len(launchers))
-	for i, l := range launchers {
+	if showTags {
+		headers = append(headers, "Tags")
+	}
+	if showDesc {
+		headers = append(headers, "Description")
+	}
+	switch sortBy {
+	case "created":
+		headers = append(headers, "Created")
+	case "last-used":
+		headers = append(headers, "Last used")
+	}
+
+	var rows [][]string
+	for _, l := range launchers {
 		launcherType := "app"
 		displayTarget := l.Target
-
-		if meta, ok := metadata[l.Name]; ok && meta != nil {
+		meta := l.Metadata
+		if meta == nil {
+			meta = &launcher.LauncherMetadata{}
+		} else {
 			launcherType = string(meta.Type)
 
 			// For stacks, show the list of targets
 			if meta.Type == launcher.TypeStack && len(meta.Targets) > 0 {
-				targetShort := strings.Join(meta.Targets, ", ")
-				if len(targetShort) > 50 {
-					targetShort = targetShort[:47] + "..."
-				}
-				displayTarget = targetShort
+				displayTarget = truncate(strings.Join(meta.Targets, ", "), 50)
+			}
+			// For actions, the action names
+			if meta.Type == launcher.TypeActions {
+				displayTarget = truncate(strings.Join(launcher.ActionNames(meta), " | "), 50)
 			}
 		}
 
-		rows[i] = []string{l.Name, launcherType, "", displayTarget}
+		row := []string{l.Name, launcherType, "", displayTarget}
+		if showTags {
+			row = append(row, strings.Join(meta.Tags, ", "))
+		}
+		if showDesc {
+			row = append(row, truncate(meta.Description, 40))
+		}
+		switch sortBy {
+		case "created":
+			row = append(row, ui.Ago(meta.CreatedAt))
+		case "last-used":
+			row = append(row, ui.Ago(usage[l.Name].Last))
+		}
+		rows = append(rows, row)
+
+		// Aliases are listed right under the launcher they belong to
+		for _, alias := range meta.Aliases {
+			aliasRow := make([]string, len(row))
+			aliasRow[0] = "  └ " + alias
+			aliasRow[1] = "alias"
+			aliasRow[3] = l.Name
+			rows = append(rows, aliasRow)
+		}
 	}
 
 	fmt.Println()
 	ui.Table(headers, rows)
 	fmt.Println()
-	ui.PrintInfo(fmt.Sprintf("Total: %d launcher(s)", len(launchers)))
+	if len(launchers) < total {
+		ui.PrintInfo(fmt.Sprintf("Showing %d of %d launcher(s)", len(launchers), total))
+	} else {
+		ui.PrintInfo(fmt.Sprintf("Total: %d launcher(s)", total))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// filterLaunchers applies the --tag, --type and --search flags
+func filterLaunchers(cmd *cobra.Command, launchers []launcher.LauncherInfo) []launcher.LauncherInfo {
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	typ, _ := cmd.Flags().GetString("type")
+	search, _ := cmd.Flags().GetString("search")
+	search = strings.ToLower(search)
+
+	var matched []launcher.LauncherInfo
+	for _, l := range launchers {
+		if len(tags) > 0 && !launcher.HasTags(l.Metadata, tags) {
+			continue
+		}
+		if typ != "" && (l.Metadata == nil || string(l.Metadata.Type) != typ) {
+			continue
+		}
+		if search != "" && !strings.Contains(searchText(l), search) {
+			continue
+		}
+		matched = append(matched, l)
+	}
+	return matched
+}
+
+// searchText is everything --search looks at, lowercased
+func searchText(l launcher.LauncherInfo) string {
+	parts := []string{l.Name, l.Target}
+	if meta := l.Metadata; meta != nil {
+		parts = append(parts, meta.Target, meta.Description)
+		parts = append(parts, meta.Targets...)
+		parts = append(parts, meta.Tags...)
+		parts = append(parts, meta.Aliases...)
+		for action, target := range meta.Actions {
+			parts = append(parts, action, target)
+		}
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
+
+// sortLaunchers orders launchers for --sort; ties fall back to the name
+func sortLaunchers(launchers []launcher.LauncherInfo, sortBy string, usage map[string]launcher.Usage) {
+	created := func(l launcher.LauncherInfo) time.Time {
+		if l.Metadata == nil {
+			return time.Time{}
+		}
+		return l.Metadata.CreatedAt
+	}
+	typeOf := func(l launcher.LauncherInfo) string {
+		if l.Metadata == nil {
+			return ""
+		}
+		return string(l.Metadata.Type)
+	}
+
+	sort.SliceStable(launchers, func(i, j int) bool {
+		a, b := launchers[i], launchers[j]
+		switch sortBy {
+		case "type":
+			if typeOf(a) != typeOf(b) {
+				return typeOf(a) < typeOf(b)
+			}
+		case "created":
+			if !created(a).Equal(created(b)) {
+				return created(a).After(created(b))
+			}
+		case "last-used":
+			if ua, ub := usage[a.Name].Last, usage[b.Name].Last; !ua.Equal(ub) {
+				return ua.After(ub)
+			}
+		}
+		return a.Name < b.Name
+	})
+}
+
+// truncate shortens s to at most limit characters, marking the cut with "..."
+func truncate(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	return s[:limit-3] + "..."
+}
+
+// foreignRecord is the structured form of a file aka does not manage
+type foreignRecord struct {
+	Name    string `json:"name" yaml:"name"`
+	Content string `json:"content" yaml:"content"`
+	Path    string `json:"path" yaml:"path"`
+}
+
+func runListForeign() error {
+	files, err := launcher.ListForeign()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to list files: %v", err))
+		return err
+	}
+
+	if structuredOutput() {
+		records := make([]foreignRecord, len(files))
+		for i, f := range files {
+			records[i] = foreignRecord{Name: f.Name, Content: f.Target, Path: launcher.ScriptPath(f.Name)}
+		}
+		return writeRecords(records, func(r foreignRecord) []string { return []string{r.Name, r.Content} })
+	}
+
+	fmt.Println()
+	if len(files) == 0 {
+		ui.PrintInfo(fmt.Sprintf("Every file in %s is managed by aka.", launcher.GetLauncherDir()))
+		fmt.Println()
+		return nil
+	}
+
+	headers := []string{"File", ui.IconArrow, "Content"}
+	rows := make([][]string, len(files))
+	for i, f := range files {
+		rows[i] = []string{f.Name, "", f.Target}
+	}
+
+	ui.Table(headers, rows)
+	fmt.Println()
+	ui.PrintInfo(fmt.Sprintf("Total: %d unmanaged file(s)", len(files)))
+	ui.PrintExample("Take one over:", "aka rename <file> <newname> --adopt")
 	fmt.Println()
 
 	return nil
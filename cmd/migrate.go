@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade launchers.json to the current schema",
+	Long: `Upgrade the launcher metadata file to the current schema version.
+
+Migrations also run automatically whenever aka loads an older file. A backup
+of the original file is written next to it before anything is changed.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationDryRun: "true"},
+	RunE:        runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	plan, err := launcher.PlanMigration()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to inspect metadata: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	if !plan.NeedsMigration() {
+		ui.PrintInfo(fmt.Sprintf("Metadata is already at schema version %d.", plan.To))
+		fmt.Println()
+		return nil
+	}
+
+	ui.KeyValue("File", plan.Path)
+	ui.KeyValue("Schema", fmt.Sprintf("v%d %s v%d", plan.From, ui.IconArrow, plan.To))
+	ui.KeyValue("Backup", plan.BackupPath)
+
+	for _, step := range plan.Steps {
+		ui.Section(fmt.Sprintf("v%d %s v%d: %s", step.From, ui.IconArrow, step.To, step.Description))
+		if len(step.Changes) == 0 {
+			ui.CurrentTheme.Muted.Println("  no launcher changes")
+			continue
+		}
+		ui.List(step.Changes)
+	}
+	fmt.Println()
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if dryRun {
+		ui.PrintInfo("Dry run: no changes written.")
+		fmt.Println()
+		return nil
+	}
+
+	if _, err := launcher.LoadMetadata(); err != nil {
+		ui.PrintError(fmt.Sprintf("Migration failed: %v", err))
+		return err
+	}
+
+	ui.SuccessBox(fmt.Sprintf("Migrated metadata to schema version %d", plan.To))
+	fmt.Println()
+
+	return nil
+}
@@ -34,9 +34,16 @@ func runOpen(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("launcher not found")
 	}
 
-	path := filepath.Join(launcher.GetLauncherDir(), shortname)
+	return runLauncher(shortname, files)
+}
+
+// runLauncher runs a launcher's script with args, attached to aka's own
+// terminal
+func runLauncher(name string, args []string) error {
+	path := filepath.Join(launcher.GetLauncherDir(), name)
 
-	execCmd := exec.Command(path, files...)
+	execCmd := exec.Command(path, args...)
+	execCmd.Stdin = os.Stdin
 	execCmd.Stdout = os.Stdout
 	execCmd.Stderr = os.Stderr
 
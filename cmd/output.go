@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// annotationStructuredOutput marks commands that honour --output and --format
+const annotationStructuredOutput = "structured-output"
+
+// Error codes reported with structured output
+const (
+	codeError                = "error"
+	codeNotFound             = "not_found"
+	codeInvalidArgument      = "invalid_argument"
+	codeNotManaged           = "not_managed"
+	codeConflict             = "conflict"
+	codeConfirmationRequired = "confirmation_required"
+	codeUnsupported          = "unsupported"
+)
+
+// Structured output state, set up before any command runs
+var (
+	outputMode     string // "", "json", "yaml", "tsv" or "template"
+	outputTemplate *template.Template
+	outputErr      error
+	// structuredOut is the real stdout; in structured mode os.Stdout points
+	// at stderr so themed messages stay out of the data
+	structuredOut io.Writer = os.Stdout
+	// lastErrorMessage is the last message a command passed to ui.PrintError
+	lastErrorMessage string
+)
+
+// codedError attaches an error code to an error for structured output
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+func withCode(code string, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// errorCode returns the code for err, "error" when nothing more specific is known
+func errorCode(err error) string {
+	var coded *codedError
+	switch {
+	case errors.As(err, &coded):
+		return coded.code
+	case errors.Is(err, launcher.ErrNotManaged):
+		return codeNotManaged
+	default:
+		return codeError
+	}
+}
+
+// configureOutput reads --output and --format. It runs before argument
+// validation so even usage errors are reported in the requested form.
+func configureOutput() {
+	output, _ := rootCmd.PersistentFlags().GetString("output")
+	format, _ := rootCmd.PersistentFlags().GetString("format")
+
+	switch {
+	case format != "":
+		tmpl, err := template.New("format").Funcs(templateFuncs).Parse(format)
+		if err != nil {
+			outputErr = withCode(codeInvalidArgument, fmt.Errorf("invalid --format template: %w", err))
+			return
+		}
+		outputMode, outputTemplate = "template", tmpl
+	case output == "" || output == "text":
+		return
+	case output == "json" || output == "yaml" || output == "tsv":
+		outputMode = output
+	default:
+		outputErr = withCode(codeInvalidArgument, fmt.Errorf("unknown output format '%s' (use json, yaml or tsv)", output))
+		outputMode = "json"
+	}
+
+	rootCmd.SilenceUsage = true
+	structuredOut = ui.UseStderr()
+	ui.CaptureErrors(func(message string) { lastErrorMessage = message })
+}
+
+// structuredOutput reports whether records rather than themed text are wanted
+func structuredOutput() bool {
+	return outputMode != ""
+}
+
+// checkStructuredOutput rejects --output on commands that do not support it
+func checkStructuredOutput(cmd *cobra.Command) error {
+	if outputErr != nil {
+		return outputErr
+	}
+	if structuredOutput() && cmd.Annotations[annotationStructuredOutput] != "true" {
+		return withCode(codeUnsupported, fmt.Errorf("'%s' does not support --output or --format", cmd.CommandPath()))
+	}
+	return nil
+}
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// writeRecords prints a list of records in the selected format; tsv returns
+// the columns of one record
+func writeRecords[T any](records []T, tsv func(T) []string) error {
+	if records == nil {
+		records = []T{}
+	}
+	switch outputMode {
+	case "tsv", "template":
+		for _, r := range records {
+			if err := writeLine(r, tsv); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return writeValue(records)
+	}
+}
+
+// writeRecord prints a single record in the selected format
+func writeRecord[T any](record T, tsv func(T) []string) error {
+	if outputMode == "tsv" || outputMode == "template" {
+		return writeLine(record, tsv)
+	}
+	return writeValue(record)
+}
+
+func writeLine[T any](record T, tsv func(T) []string) error {
+	if outputMode == "template" {
+		if err := outputTemplate.Execute(structuredOut, record); err != nil {
+			return withCode(codeInvalidArgument, fmt.Errorf("--format: %w", err))
+		}
+		_, err := fmt.Fprintln(structuredOut)
+		return err
+	}
+
+	cells := tsv(record)
+	for i, cell := range cells {
+		cells[i] = strings.NewReplacer("\t", " ", "\n", " ").Replace(cell)
+	}
+	_, err := fmt.Fprintln(structuredOut, strings.Join(cells, "\t"))
+	return err
+}
+
+func writeValue(v any) error {
+	if outputMode == "yaml" {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = structuredOut.Write(data)
+		return err
+	}
+
+	enc := json.NewEncoder(structuredOut)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeError reports a failed command on stderr as a record with a code
+func writeError(err error) {
+	message, code := lastErrorMessage, errorCode(err)
+	if message == "" {
+		// Commands report their own errors; anything else comes from cobra's
+		// argument checks
+		message = err.Error()
+		if code == codeError {
+			code = codeInvalidArgument
+		}
+	}
+
+	if outputMode == "tsv" {
+		fmt.Fprintf(os.Stderr, "error\t%s\t%s\n", code, message)
+		return
+	}
+
+	record := map[string]map[string]string{"error": {"code": code, "message": message}}
+	if outputMode == "yaml" {
+		data, _ := yaml.Marshal(record)
+		os.Stderr.Write(data)
+		return
+	}
+	enc := json.NewEncoder(os.Stderr)
+	enc.SetEscapeHTML(false)
+	enc.Encode(record)
+}
+
+// launcherRecord is the structured form of a launcher. Secrets are never included.
+type launcherRecord struct {
+	Name        string            `json:"name" yaml:"name"`
+	Type        string            `json:"type" yaml:"type"`
+	Target      string            `json:"target,omitempty" yaml:"target,omitempty"`
+	Targets     []string          `json:"targets,omitempty" yaml:"targets,omitempty"`
+	Actions     map[string]string `json:"actions,omitempty" yaml:"actions,omitempty"`
+	Env         map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	SSH         *sshRecord        `json:"ssh,omitempty" yaml:"ssh,omitempty"`
+	Description string            `json:"description" yaml:"description"`
+	Tags        []string          `json:"tags" yaml:"tags"`
+	Aliases     []string          `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	CreatedAt   string            `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	LastUsed    string            `json:"last_used,omitempty" yaml:"last_used,omitempty"`
+	UseCount    int               `json:"use_count" yaml:"use_count"`
+	Script      string            `json:"script" yaml:"script"`
+}
+
+type sshRecord struct {
+	Port          int    `json:"port,omitempty" yaml:"port,omitempty"`
+	KeyFile       string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+	PasswordSaved bool   `json:"password_saved" yaml:"password_saved"`
+}
+
+// newLauncherRecord builds the record for a launcher; meta may be nil for
+// scripts aka knows only by their target
+func newLauncherRecord(name, target string, meta *launcher.LauncherMetadata, usage launcher.Usage) launcherRecord {
+	r := launcherRecord{
+		Name:     name,
+		Type:     string(launcher.TypeApplication),
+		Target:   target,
+		Tags:     []string{},
+		UseCount: usage.Count,
+		Script:   launcher.ScriptPath(name),
+	}
+	if !usage.Last.IsZero() {
+		r.LastUsed = usage.Last.UTC().Format(time.RFC3339)
+	}
+	if meta == nil {
+		return r
+	}
+
+	r.Type = string(meta.Type)
+	r.Target = meta.Target
+	r.Targets = meta.Targets
+	r.Actions = meta.Actions
+	r.Env = meta.Env
+	r.Description = meta.Description
+	if len(meta.Tags) > 0 {
+		r.Tags = meta.Tags
+	}
+	r.Aliases = meta.Aliases
+	if !meta.CreatedAt.IsZero() {
+		r.CreatedAt = meta.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if ssh := meta.SSHConfig; ssh != nil {
+		r.SSH = &sshRecord{Port: ssh.Port, KeyFile: ssh.KeyFile, PasswordSaved: ssh.Password != ""}
+	}
+	return r
+}
+
+// launcherTSV is the column order of launcher records in --output tsv:
+// name, type, target, tags, description
+func launcherTSV(r launcherRecord) []string {
+	target := r.Target
+	if len(r.Targets) > 0 {
+		target = strings.Join(r.Targets, ",")
+	}
+	return []string{r.Name, r.Type, target, strings.Join(r.Tags, ","), r.Description}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var pickCmd = &cobra.Command{
+	Use:   "pick [query]",
+	Short: "Search launchers and run one",
+	Long: `Open a fuzzy finder over your launchers' names, descriptions, tags and
+targets, with the selected launcher's script beside the list. Enter runs the
+launcher, Esc closes the finder.
+
+Launchers you ran often and recently come first. Running aka without a
+command in a terminal opens the finder too.
+
+Keys: type to filter, ↑/↓ or Ctrl-P/Ctrl-N to move, Ctrl-U to clear.`,
+	Example: `  aka pick
+  aka pick prod
+  cd "$(aka pick --print)"`,
+	RunE: runPick,
+}
+
+func init() {
+	rootCmd.AddCommand(pickCmd)
+	pickCmd.Flags().Bool("print", false, "Print the chosen launcher's name instead of running it")
+}
+
+// runRoot opens the picker when aka runs without a command in a terminal,
+// and shows the help otherwise
+func runRoot(cmd *cobra.Command, args []string) error {
+	if structuredOutput() || !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return cmd.Help()
+	}
+	return runPick(cmd, args)
+}
+
+func runPick(cmd *cobra.Command, args []string) error {
+	launchers, err := launcher.List()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to list launchers: %v", err))
+		return err
+	}
+	if len(launchers) == 0 {
+		fmt.Println()
+		ui.PrintInfo("No launchers configured yet.")
+		fmt.Println()
+		ui.PrintExample("Create your first launcher:", "aka add ag \"Adobe Acrobat\"")
+		fmt.Println()
+		return nil
+	}
+
+	usage, err := launcher.LoadUsage()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to read usage log: %v", err))
+		return err
+	}
+
+	items := make([]ui.PickerItem, len(launchers))
+	for i, l := range launchers {
+		items[i] = ui.PickerItem{
+			Name:    l.Name,
+			Detail:  pickDetail(l),
+			Preview: pickPreview(l),
+			Rank:    usage[l.Name].Frecency,
+		}
+	}
+
+	choice, err := ui.Pick(items, strings.Join(args, " "))
+	if errors.Is(err, ui.ErrPickCancelled) {
+		return nil
+	}
+	if err != nil {
+		ui.PrintError(err.Error())
+		return err
+	}
+
+	name := launchers[choice].Name
+	if printName, _ := cmd.Flags().GetBool("print"); printName {
+		fmt.Println(name)
+		return nil
+	}
+	return runLauncher(name, nil)
+}
+
+// pickDetail is what the picker shows and searches beside a launcher's name
+func pickDetail(l launcher.LauncherInfo) string {
+	meta := l.Metadata
+	if meta == nil {
+		return l.Target
+	}
+
+	var parts []string
+	if meta.Description != "" {
+		parts = append(parts, meta.Description)
+	}
+	for _, tag := range meta.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	switch meta.Type {
+	case launcher.TypeStack:
+		parts = append(parts, strings.Join(meta.Targets, ", "))
+	case launcher.TypeActions:
+		parts = append(parts, strings.Join(launcher.ActionNames(meta), " | "))
+	default:
+		parts = append(parts, meta.Target)
+	}
+	parts = append(parts, meta.Aliases...)
+	return strings.Join(parts, "  ")
+}
+
+// pickPreview is the launcher's script with saved passwords masked
+func pickPreview(l launcher.LauncherInfo) string {
+	data, err := os.ReadFile(launcher.ScriptPath(l.Name))
+	if err != nil {
+		return ""
+	}
+	script := string(data)
+	if meta := l.Metadata; meta != nil && meta.SSHConfig != nil && meta.SSHConfig.Password != "" {
+		script = strings.ReplaceAll(script, meta.SSHConfig.Password, maskedPassword)
+	}
+	return script
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage launcher profiles",
+	Long: `Profiles are separate launcher sets, each with its own bin directory and
+metadata file. Use them to keep work, personal and per-client launchers apart.
+
+Select a profile for one command with --profile or AKA_PROFILE, or make it the
+default with 'aka profile use'.`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List profiles",
+	Args:    cobra.NoArgs,
+	RunE:    runProfileList,
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <profile>",
+	Short: "Create a new profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProfileCreate,
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <profile>",
+	Short: "Make a profile the default",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProfileUse,
+}
+
+var profileCurrentCmd = &cobra.Command{
+	Use:         "current",
+	Short:       "Show the active profile",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationMachineOutput: "true"},
+	RunE:        runProfileCurrent,
+}
+
+var profileRemoveCmd = &cobra.Command{
+	Use:     "remove <profile>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a profile and its launchers",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runProfileRemove,
+}
+
+var profileMoveCmd = &cobra.Command{
+	Use:     "move <shortname> <profile>",
+	Aliases: []string{"mv"},
+	Short:   "Move a launcher into another profile",
+	Args:    cobra.ExactArgs(2),
+	RunE:    runProfileMove,
+}
+
+var profileEnvCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print shell code that puts the active profile on PATH",
+	Long: `Print an export line that adds the active profile's bin directory to PATH.
+
+Add this to your shell config so new shells follow 'aka profile use':
+
+  eval "$(aka profile env)"`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationMachineOutput: "true"},
+	RunE:        runProfileEnv,
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileCurrentCmd)
+	profileCmd.AddCommand(profileRemoveCmd)
+	profileCmd.AddCommand(profileMoveCmd)
+	profileCmd.AddCommand(profileEnvCmd)
+	profileRemoveCmd.Flags().BoolP("force", "f", false, "Remove without confirmation")
+}
+
+func runProfileList(cmd *cobra.Command, args []string) error {
+	profiles, err := launcher.ListProfiles()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to list profiles: %v", err))
+		return err
+	}
+
+	active := launcher.ActiveProfile()
+
+	headers := []string{"", "Profile", "Launchers", "Directory"}
+	rows := make([][]string, len(profiles))
+	for i, name := range profiles {
+		marker := ""
+		if name == active {
+			marker = ui.IconPointer
+		}
+
+		var count int
+		var dir string
+		_ = launcher.InProfile(name, func() error {
+			dir = launcher.GetLauncherDir()
+			launchers, err := launcher.List()
+			count = len(launchers)
+			return err
+		})
+
+		rows[i] = []string{marker, name, fmt.Sprintf("%d", count), dir}
+	}
+
+	fmt.Println()
+	ui.Table(headers, rows)
+	fmt.Println()
+
+	return nil
+}
+
+func runProfileCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if !isValidShortname(name) {
+		ui.PrintError("Invalid profile name. Use only alphanumeric characters, hyphens, and underscores.")
+		return fmt.Errorf("invalid profile name")
+	}
+
+	if err := launcher.CreateProfile(name); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to create profile: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Created profile '%s'", name))
+	ui.PrintExample("Switch to it:", fmt.Sprintf("aka profile use %s", name))
+	ui.PrintExample("Or use it for a single command:", fmt.Sprintf("aka --profile %s add ...", name))
+	fmt.Println()
+
+	return nil
+}
+
+func runProfileUse(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	if err := launcher.UseProfile(name); err != nil {
+		ui.PrintError(err.Error())
+		return err
+	}
+
+	var dir string
+	_ = launcher.InProfile(name, func() error {
+		dir = launcher.GetLauncherDir()
+		return nil
+	})
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Now using profile '%s'", name))
+	ui.KeyValue("Launchers", dir)
+	fmt.Println()
+	ui.PrintInfo("Launchers from this profile are only found if its directory is on your PATH.")
+	ui.PrintInfo("Add this to your shell config to follow the active profile:")
+	fmt.Println()
+	ui.PrintCommand(`eval "$(aka profile env)"`)
+	fmt.Println()
+
+	return nil
+}
+
+func runProfileCurrent(cmd *cobra.Command, args []string) error {
+	fmt.Println(launcher.ActiveProfile())
+	return nil
+}
+
+func runProfileRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force {
+		if !ui.Confirm(fmt.Sprintf("Remove profile '%s' and all of its launchers?", name)) {
+			ui.PrintInfo("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := launcher.RemoveProfile(name); err != nil {
+		ui.PrintError(err.Error())
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Removed profile '%s'", name))
+	fmt.Println()
+
+	return nil
+}
+
+func runProfileMove(cmd *cobra.Command, args []string) error {
+	shortname := args[0]
+	profile := args[1]
+
+	if !launcher.Exists(shortname) {
+		ui.PrintError(fmt.Sprintf("Launcher '%s' does not exist", shortname))
+		return fmt.Errorf("launcher not found")
+	}
+
+	if err := launcher.MoveToProfile(shortname, profile); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to move launcher: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Moved launcher '%s' from '%s' to '%s'", shortname, launcher.ActiveProfile(), profile))
+	fmt.Println()
+
+	return nil
+}
+
+func runProfileEnv(cmd *cobra.Command, args []string) error {
+	fmt.Printf("export PATH=\"%s:$PATH\"\n", launcher.GetLauncherDir())
+	return nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var rebuildCmd = &cobra.Command{
+	Use:   "rebuild [--all | shortname...]",
+	Short: "Regenerate launcher scripts from metadata",
+	Long: `Regenerate launcher scripts from launchers.json so they pick up
+improvements to the script generator.
+
+Only scripts that differ from what aka would generate today are rewritten, and
+a diff is shown before anything changes. Scripts that were edited by hand are
+skipped unless --discard-edits is given.`,
+	Annotations: map[string]string{annotationDryRun: "true"},
+	RunE:        runRebuild,
+}
+
+func init() {
+	rootCmd.AddCommand(rebuildCmd)
+	rebuildCmd.Flags().Bool("all", false, "Rebuild every launcher")
+	rebuildCmd.Flags().BoolP("force", "f", false, "Rebuild without confirmation")
+	rebuildCmd.Flags().Bool("discard-edits", false, "Also rebuild scripts that were edited by hand")
+}
+
+func runRebuild(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(args) > 0) {
+		ui.PrintError("Specify launcher names or --all.")
+		return fmt.Errorf("nothing to rebuild")
+	}
+
+	items, err := launcher.PlanRebuild(args)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to plan rebuild: %v", err))
+		return err
+	}
+
+	discardEdits, _ := cmd.Flags().GetBool("discard-edits")
+
+	var pending []launcher.RebuildItem
+	fmt.Println()
+	for _, item := range items {
+		ui.Section(fmt.Sprintf("%s (%s)", item.Name, item.Reason()))
+		if item.Edited && !discardEdits {
+			ui.CurrentTheme.Muted.Println("  skipped: use --discard-edits to replace it")
+			continue
+		}
+		ui.Diff(item.Diff())
+		pending = append(pending, item)
+	}
+
+	if len(pending) == 0 {
+		if len(items) == 0 {
+			ui.SuccessBox("All launchers are up to date")
+		}
+		fmt.Println()
+		return nil
+	}
+	fmt.Println()
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if dryRun {
+		ui.PrintInfo("Dry run: no changes written.")
+		fmt.Println()
+		return nil
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force && !ui.Confirm(fmt.Sprintf("Rebuild %d launcher(s)?", len(pending))) {
+		ui.PrintInfo("Cancelled.")
+		return nil
+	}
+
+	for _, item := range pending {
+		if err := item.Apply(); err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to rebuild '%s': %v", item.Name, err))
+			return err
+		}
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Rebuilt %d launcher(s)", len(pending)))
+	fmt.Println()
+
+	return nil
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/dorochadev/aka/launcher"
 	"github.com/dorochadev/aka/ui"
@@ -9,44 +11,159 @@ import (
 )
 
 var removeCmd = &cobra.Command{
-	Use:     "remove <shortname>",
+	Use:     "remove <shortname>... | --tag <tag>",
 	Aliases: []string{"rm", "delete"},
 	Short:   "Remove a launcher",
-	Long:    `Remove an existing launcher by its shortname.`,
-	Args:    cobra.ExactArgs(1),
-	RunE:    runRemove,
+	Long: `Remove existing launchers by shortname, or every launcher carrying the
+tags given with --tag.`,
+	Example: `  aka remove gh
+  aka remove --tag old`,
+	Annotations: map[string]string{annotationStructuredOutput: "true", annotationDryRun: "true"},
+	RunE:        runRemove,
 }
 
 func init() {
 	rootCmd.AddCommand(removeCmd)
 	removeCmd.Flags().BoolP("force", "f", false, "Remove without confirmation")
+	removeCmd.Flags().Bool("adopt", false, "Remove a file even though aka did not create it")
+	removeCmd.Flags().StringSliceP("tag", "t", nil, "Remove every launcher with all of these tags")
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
-	shortname := args[0]
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	if len(args) == 0 && len(tags) == 0 {
+		ui.PrintError("Name a launcher to remove, or select launchers with --tag.")
+		return withCode(codeInvalidArgument, fmt.Errorf("no launcher given"))
+	}
+
+	names := args
+	if len(tags) > 0 {
+		store, err := launcher.LoadMetadata()
+		if err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+			return err
+		}
+		tagged := taggedLaunchers(store, tags)
+		if len(tagged) == 0 && len(names) == 0 {
+			if structuredOutput() {
+				return writeRecords([]launcherRecord{}, launcherTSV)
+			}
+			ui.PrintInfo(fmt.Sprintf("No launchers are tagged %s.", strings.Join(launcher.NormalizeTags(tags), ", ")))
+			return nil
+		}
+		for _, name := range tagged {
+			if !containsString(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if !launcher.Exists(name) {
+			ui.PrintError(fmt.Sprintf("Launcher '%s' does not exist", name))
+			return withCode(codeNotFound, fmt.Errorf("launcher not found"))
+		}
+		if owner := launcher.AliasOwner(name); owner != "" {
+			ui.PrintError(fmt.Sprintf("'%s' is an alias of '%s'. Remove it with 'aka alias remove %s'.", name, owner, name))
+			return withCode(codeInvalidArgument, fmt.Errorf("name is an alias"))
+		}
+		if err := adoptIfRequested(cmd, name); err != nil {
+			return err
+		}
+	}
 
-	if !launcher.Exists(shortname) {
-		ui.PrintError(fmt.Sprintf("Launcher '%s' does not exist", shortname))
-		return fmt.Errorf("launcher not found")
+	if dryRun(cmd) {
+		var changes []launcher.FileChange
+		for _, name := range names {
+			preview, err := launcher.PreviewRemove(name)
+			if err != nil {
+				ui.PrintError(err.Error())
+				return err
+			}
+			changes = append(changes, preview...)
+		}
+		return printFileChanges(dedupeChanges(changes))
 	}
 
 	force, _ := cmd.Flags().GetBool("force")
+	if !force && structuredOutput() {
+		ui.PrintError("Removing launchers needs confirmation. Use --force.")
+		return withCode(codeConfirmationRequired, fmt.Errorf("confirmation required"))
+	}
 	if !force {
-		confirm := ui.Confirm(fmt.Sprintf("Remove launcher '%s'?", shortname))
-		if !confirm {
+		prompt := fmt.Sprintf("Remove launcher '%s'?", names[0])
+		if len(names) > 1 {
+			fmt.Println()
+			ui.List(names)
+			fmt.Println()
+			prompt = fmt.Sprintf("Remove these %d launchers?", len(names))
+		}
+		if !ui.Confirm(prompt) {
 			ui.PrintInfo("Cancelled.")
 			return nil
 		}
 	}
 
-	if err := launcher.Remove(shortname); err != nil {
-		ui.PrintError(fmt.Sprintf("Failed to remove launcher: %v", err))
-		return err
+	// Capture the records before the launchers are gone
+	var records []launcherRecord
+	if structuredOutput() {
+		list, err := launcher.List()
+		if err != nil {
+			return err
+		}
+		usage, _ := launcher.LoadUsage()
+		for _, l := range list {
+			if containsString(names, l.Name) {
+				records = append(records, newLauncherRecord(l.Name, l.Target, l.Metadata, usage[l.Name]))
+			}
+		}
+	}
+
+	for _, name := range names {
+		if err := launcher.Remove(name); err != nil {
+			ui.PrintError(fmt.Sprintf("Failed to remove launcher '%s': %v", name, err))
+			return err
+		}
+	}
+
+	if structuredOutput() {
+		return writeRecords(records, launcherTSV)
 	}
 
 	fmt.Println()
-	ui.SuccessBox(fmt.Sprintf("Removed launcher '%s'", shortname))
+	if len(names) == 1 {
+		ui.SuccessBox(fmt.Sprintf("Removed launcher '%s'", names[0]))
+	} else {
+		ui.SuccessBox(fmt.Sprintf("Removed %d launchers", len(names)))
+	}
+	ui.PrintInfo("Changed your mind? Run 'aka undo' or 'aka trash restore <name>'.")
 	fmt.Println()
 
 	return nil
 }
+
+// taggedLaunchers returns the sorted names of launchers carrying all of tags
+func taggedLaunchers(store launcher.MetadataStore, tags []string) []string {
+	var names []string
+	for name, meta := range store {
+		if meta != nil && launcher.HasTags(meta, tags) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dedupeChanges keeps the first change to each file, so the shared metadata
+// index is listed once when several launchers change
+func dedupeChanges(changes []launcher.FileChange) []launcher.FileChange {
+	seen := make(map[string]bool)
+	var kept []launcher.FileChange
+	for _, c := range changes {
+		if !seen[c.Path] {
+			seen[c.Path] = true
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
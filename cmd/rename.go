@@ -9,16 +9,19 @@ import (
 )
 
 var renameCmd = &cobra.Command{
-	Use:     "rename <oldname> <newname>",
-	Aliases: []string{"mv"},
-	Short:   "Rename a launcher",
-	Long:    `Rename an existing launcher without changing its target application.`,
-	Args:    cobra.ExactArgs(2),
-	RunE:    runRename,
+	Use:         "rename <oldname> <newname>",
+	Aliases:     []string{"mv"},
+	Short:       "Rename a launcher",
+	Long:        `Rename an existing launcher without changing its target application.`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: map[string]string{annotationDryRun: "true"},
+	RunE:        runRename,
 }
 
 func init() {
 	rootCmd.AddCommand(renameCmd)
+	renameCmd.Flags().Bool("adopt", false, "Rename a file even though aka did not create it")
+	renameCmd.Flags().Bool("allow-shadow", false, "Use a name that is already a command on PATH or a shell builtin")
 }
 
 func runRename(cmd *cobra.Command, args []string) error {
@@ -31,18 +34,35 @@ func runRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid shortname")
 	}
 
+	if err := checkShadowing(cmd, newName); err != nil {
+		return err
+	}
+
 	// Check if old launcher exists
 	if !launcher.Exists(oldName) {
 		ui.PrintError(fmt.Sprintf("Launcher '%s' does not exist", oldName))
 		return fmt.Errorf("launcher not found")
 	}
 
+	if err := adoptIfRequested(cmd, oldName); err != nil {
+		return err
+	}
+
 	// Check if new name already exists
 	if launcher.Exists(newName) {
 		ui.PrintError(fmt.Sprintf("Launcher '%s' already exists", newName))
 		return fmt.Errorf("launcher already exists")
 	}
 
+	if dryRun(cmd) {
+		changes, err := launcher.PreviewRename(oldName, newName)
+		if err != nil {
+			ui.PrintError(err.Error())
+			return err
+		}
+		return printFileChanges(changes)
+	}
+
 	// Rename the launcher
 	if err := launcher.Rename(oldName, newName); err != nil {
 		ui.PrintError(fmt.Sprintf("Failed to rename launcher: %v", err))
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/dorochadev/aka/launcher"
 	"github.com/dorochadev/aka/setup"
 	"github.com/dorochadev/aka/ui"
 	"github.com/spf13/cobra"
@@ -13,6 +14,10 @@ var (
 	version = "1.0.0"
 )
 
+// annotationMachineOutput marks commands whose stdout is read by a shell or
+// script, so nothing else may be printed to it
+const annotationMachineOutput = "machine-output"
+
 // rootCmd represents the base command
 var rootCmd = &cobra.Command{
 	Use:   "aka",
@@ -23,20 +28,39 @@ Instead of storing shell aliases, aka creates small executable files that
 open your applications. The filesystem itself acts as the database.`,
 	Version: version,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkStructuredOutput(cmd); err != nil {
+			return err
+		}
+		if err := checkDryRun(cmd); err != nil {
+			return err
+		}
+		if err := applyGlobalFlags(cmd); err != nil {
+			return err
+		}
+		if cmd.Annotations[annotationMachineOutput] == "true" || structuredOutput() {
+			setup.SetQuiet()
+		}
 		return setup.EnsureSetup()
 	},
+	RunE:          runRoot,
 	SilenceErrors: true, // We'll handle errors ourselves
 }
 
 // Execute runs the root command
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		ui.PrintError(err.Error())
+		if structuredOutput() {
+			writeError(err)
+		} else {
+			ui.PrintError(err.Error())
+		}
 		os.Exit(1)
 	}
 }
 
 func init() {
+	cobra.OnInitialize(configureOutput)
+
 	// Disable default completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
@@ -50,6 +74,40 @@ func init() {
 
 	// Set custom help function for all subcommands
 	rootCmd.PersistentFlags().BoolP("help", "h", false, "Show help for command")
+
+	// Relocate aka's files; AKA_HOME moves everything at once
+	rootCmd.PersistentFlags().String("bin-dir", "", "Launcher directory (overrides AKA_BIN_DIR)")
+	rootCmd.PersistentFlags().String("config-dir", "", "Configuration directory (overrides AKA_CONFIG_DIR)")
+	rootCmd.PersistentFlags().StringP("profile", "P", "", "Profile to use (overrides AKA_PROFILE)")
+
+	// Machine-readable output for scripts
+	rootCmd.PersistentFlags().String("output", "", "Output format: text, json, yaml or tsv")
+	rootCmd.PersistentFlags().String("format", "", "Print each record with a Go template, e.g. '{{.Name}} {{.Type}}'")
+
+	// Validate and preview changes without writing anything
+	rootCmd.PersistentFlags().Bool("dry-run", false, "Show the files that would change without writing anything")
+}
+
+// applyGlobalFlags passes the global location flags on to the launcher package
+func applyGlobalFlags(cmd *cobra.Command) error {
+	if profile, _ := cmd.Flags().GetString("profile"); profile != "" {
+		launcher.SetProfile(profile)
+	}
+	if dir, _ := cmd.Flags().GetString("bin-dir"); dir != "" {
+		launcher.SetBinDir(dir)
+	}
+	if dir, _ := cmd.Flags().GetString("config-dir"); dir != "" {
+		launcher.SetConfigDir(dir)
+	}
+
+	// Profile management commands handle missing profiles themselves
+	if cmd.Parent() != profileCmd {
+		if profile := launcher.ActiveProfile(); !launcher.ProfileExists(profile) {
+			return fmt.Errorf("profile '%s' does not exist (create it with 'aka profile create %s')", profile, profile)
+		}
+	}
+
+	return nil
 }
 
 func styleText(s string) string {
@@ -93,6 +151,12 @@ func customHelp(cmd *cobra.Command, args []string) {
 		ui.CurrentTheme.Primary.Println("FLAGS")
 		ui.CurrentTheme.Body.Print("  -h, --help       Show this help message\n")
 		ui.CurrentTheme.Body.Print("  -v, --version    Show version information\n")
+		ui.CurrentTheme.Body.Print("      --bin-dir    Launcher directory (overrides AKA_BIN_DIR)\n")
+		ui.CurrentTheme.Body.Print("      --config-dir Configuration directory (overrides AKA_CONFIG_DIR)\n")
+		ui.CurrentTheme.Body.Print("  -P, --profile    Profile to use (overrides AKA_PROFILE)\n")
+		ui.CurrentTheme.Body.Print("      --output     Output format: text, json, yaml or tsv\n")
+		ui.CurrentTheme.Body.Print("      --format     Print each record with a Go template\n")
+		ui.CurrentTheme.Body.Print("      --dry-run    Show what would change without writing anything\n")
 	}
 
 	fmt.Println()
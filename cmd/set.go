@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var setCmd = &cobra.Command{
+	Use:   "set <shortname>",
+	Short: "Change fields of a launcher",
+	Long: `Change individual fields of a launcher and regenerate its script, without
+retyping the rest. The result is checked the same way 'aka add' checks a new
+launcher. Adding a target to a single launcher turns it into a stack.`,
+	Example: `  aka set prod --port 2222
+  aka set dev --env DEBUG=0
+  aka set dev --add-target Slack
+  aka set proj --action lint="golangci-lint run"
+  aka set prod --desc "Production API" --tag infra`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationStructuredOutput: "true"},
+	RunE:        runSet,
+}
+
+var unsetCmd = &cobra.Command{
+	Use:   "unset <shortname>",
+	Short: "Clear fields of a launcher",
+	Long:  `Remove individual fields from a launcher and regenerate its script.`,
+	Example: `  aka unset dev --env DEBUG
+  aka unset dev --target Slack
+  aka unset prod --key --port`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationStructuredOutput: "true"},
+	RunE:        runUnset,
+}
+
+func init() {
+	rootCmd.AddCommand(setCmd)
+	setCmd.Flags().String("target", "", "Replace the target")
+	setCmd.Flags().String("type", "", "Change the type (app, url, ssh, cmd, dir, actions)")
+	setCmd.Flags().StringSlice("add-target", nil, "Add a target, turning the launcher into a stack")
+	setCmd.Flags().StringArrayP("action", "a", nil, "Add or replace an action as name=target (repeatable)")
+	setCmd.Flags().StringToString("env", nil, "Set environment variables (key=value)")
+	setCmd.Flags().Int("port", 0, "SSH port")
+	setCmd.Flags().StringP("key", "k", "", "SSH key file path")
+	setCmd.Flags().StringP("desc", "d", "", "Description")
+	setCmd.Flags().StringSliceP("tag", "t", nil, "Add tags")
+
+	rootCmd.AddCommand(unsetCmd)
+	unsetCmd.Flags().StringSlice("env", nil, "Remove environment variables by name")
+	unsetCmd.Flags().StringSlice("target", nil, "Remove targets from a stack")
+	unsetCmd.Flags().StringSliceP("action", "a", nil, "Remove actions by name")
+	unsetCmd.Flags().Bool("port", false, "Go back to the default SSH port")
+	unsetCmd.Flags().Bool("key", false, "Stop using an SSH key file")
+	unsetCmd.Flags().Bool("password", false, "Forget the saved SSH password")
+	unsetCmd.Flags().Bool("desc", false, "Clear the description")
+	unsetCmd.Flags().StringSliceP("tag", "t", nil, "Remove tags")
+}
+
+func runSet(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if flags.NFlag() == 0 {
+		ui.PrintError("Nothing to set. See 'aka set --help' for the fields you can change.")
+		return withCode(codeInvalidArgument, fmt.Errorf("nothing to set"))
+	}
+
+	actions, err := parseActions(cmd)
+	if err != nil {
+		ui.PrintError(err.Error())
+		return withCode(codeInvalidArgument, err)
+	}
+
+	return updateFields(args[0], func(meta *launcher.LauncherMetadata) error {
+		if flags.Changed("type") {
+			typ, _ := flags.GetString("type")
+			meta.Type = launcher.LauncherType(typ)
+		}
+		if flags.Changed("target") {
+			target, _ := flags.GetString("target")
+			if meta.Type == launcher.TypeStack {
+				return fmt.Errorf("a stack has several targets: use --add-target and 'aka unset --target'")
+			}
+			meta.Target = target
+		}
+		if flags.Changed("add-target") {
+			targets, _ := flags.GetStringSlice("add-target")
+			if meta.Type != launcher.TypeStack {
+				meta.Targets = []string{meta.Target}
+				meta.Target = ""
+				meta.Type = launcher.TypeStack
+			}
+			meta.Targets = append(meta.Targets, targets...)
+		}
+		if len(actions) > 0 {
+			if meta.Type != launcher.TypeActions {
+				return fmt.Errorf("only launchers with actions have actions (switch with --type actions)")
+			}
+			if meta.Actions == nil {
+				meta.Actions = make(map[string]string)
+			}
+			for name, target := range actions {
+				meta.Actions[name] = target
+			}
+		}
+		if flags.Changed("env") {
+			env, _ := flags.GetStringToString("env")
+			if meta.Env == nil {
+				meta.Env = make(map[string]string)
+			}
+			for k, v := range env {
+				meta.Env[k] = v
+			}
+		}
+		if flags.Changed("port") || flags.Changed("key") {
+			if meta.Type != launcher.TypeSSH {
+				return fmt.Errorf("only SSH launchers have a port and key file")
+			}
+			if meta.SSHConfig == nil {
+				meta.SSHConfig = &launcher.SSHConfig{}
+			}
+			if flags.Changed("port") {
+				meta.SSHConfig.Port, _ = flags.GetInt("port")
+			}
+			if flags.Changed("key") {
+				meta.SSHConfig.KeyFile, _ = flags.GetString("key")
+			}
+		}
+		if flags.Changed("desc") {
+			meta.Description, _ = flags.GetString("desc")
+		}
+		if flags.Changed("tag") {
+			tags, _ := flags.GetStringSlice("tag")
+			meta.Tags = append(meta.Tags, tags...)
+		}
+		return nil
+	})
+}
+
+func runUnset(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+	if flags.NFlag() == 0 {
+		ui.PrintError("Nothing to unset. See 'aka unset --help' for the fields you can clear.")
+		return withCode(codeInvalidArgument, fmt.Errorf("nothing to unset"))
+	}
+
+	return updateFields(args[0], func(meta *launcher.LauncherMetadata) error {
+		if flags.Changed("env") {
+			keys, _ := flags.GetStringSlice("env")
+			for _, k := range keys {
+				if _, ok := meta.Env[k]; !ok {
+					return fmt.Errorf("environment variable '%s' is not set", k)
+				}
+				delete(meta.Env, k)
+			}
+		}
+		if flags.Changed("target") {
+			if meta.Type != launcher.TypeStack {
+				return fmt.Errorf("only stacks have several targets")
+			}
+			remove, _ := flags.GetStringSlice("target")
+			var kept []string
+			for _, t := range meta.Targets {
+				if !containsString(remove, t) {
+					kept = append(kept, t)
+				}
+			}
+			if len(kept) == len(meta.Targets) {
+				return fmt.Errorf("none of the targets are in the stack")
+			}
+			meta.Targets = kept
+		}
+		if flags.Changed("action") {
+			if meta.Type != launcher.TypeActions {
+				return fmt.Errorf("only launchers with actions have actions")
+			}
+			names, _ := flags.GetStringSlice("action")
+			for _, name := range names {
+				if _, ok := meta.Actions[name]; !ok {
+					return fmt.Errorf("there is no action '%s'", name)
+				}
+				delete(meta.Actions, name)
+			}
+		}
+		if ssh := meta.SSHConfig; ssh != nil {
+			if port, _ := flags.GetBool("port"); port {
+				ssh.Port = 0
+			}
+			if key, _ := flags.GetBool("key"); key {
+				ssh.KeyFile = ""
+			}
+			if password, _ := flags.GetBool("password"); password {
+				ssh.Password = ""
+			}
+		}
+		if desc, _ := flags.GetBool("desc"); desc {
+			meta.Description = ""
+		}
+		if flags.Changed("tag") {
+			tags, _ := flags.GetStringSlice("tag")
+			remove := launcher.NormalizeTags(tags)
+			var kept []string
+			for _, tag := range meta.Tags {
+				if !containsString(remove, tag) {
+					kept = append(kept, tag)
+				}
+			}
+			meta.Tags = kept
+		}
+		return nil
+	})
+}
+
+// updateFields applies edit to a launcher and reports what changed
+func updateFields(name string, edit func(meta *launcher.LauncherMetadata) error) error {
+	store, err := launcher.LoadMetadata()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+	before := store[name]
+	if before == nil {
+		ui.PrintError(fmt.Sprintf("Launcher '%s' does not exist", name))
+		return withCode(codeNotFound, fmt.Errorf("launcher not found"))
+	}
+
+	if err := launcher.Update(name, edit); err != nil {
+		ui.PrintError(err.Error())
+		return withCode(codeInvalidArgument, err)
+	}
+
+	after, err := launcher.GetMetadata(name)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+
+	if structuredOutput() {
+		usage, _ := launcher.LoadUsage()
+		return writeRecord(newLauncherRecord(name, "", after, usage[name]), launcherTSV)
+	}
+
+	changes := launcher.FieldChanges(before, after)
+	passwordCleared := before.SSHConfig != nil && before.SSHConfig.Password != "" &&
+		(after.SSHConfig == nil || after.SSHConfig.Password == "")
+	if passwordCleared {
+		changes = append(changes, "ssh.password: removed")
+	}
+
+	fmt.Println()
+	if len(changes) == 0 {
+		ui.PrintInfo(fmt.Sprintf("'%s' already had those values.", name))
+	} else {
+		ui.SuccessBox(fmt.Sprintf("Updated launcher '%s'", name))
+		ui.List(changes)
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init [bash|zsh|fish]",
+	Short: "Print shell code that defines every launcher as a function",
+	Long: `Print shell code that defines every launcher as a shell function.
+
+Functions run inside your shell, so they work without the launcher
+directory on PATH, and launchers can change the shell itself: directory
+launchers (aka add proj ~/code/proj --type dir) jump there, and commands
+like 'cd' or 'source' take effect. Launcher files keep working alongside.
+
+Add this to your shell config (the shell is detected from $SHELL if omitted):
+
+  eval "$(aka shell-init zsh)"          # ~/.zshrc
+  eval "$(aka shell-init bash)"         # ~/.bashrc
+  aka shell-init fish | source          # ~/.config/fish/config.fish
+
+Functions are defined when the shell starts; open a new shell or eval
+again after adding launchers.`,
+	Args:        cobra.MaximumNArgs(1),
+	ValidArgs:   launcher.ShellInitShells,
+	Annotations: map[string]string{annotationMachineOutput: "true"},
+	RunE:        runShellInit,
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
+
+func runShellInit(cmd *cobra.Command, args []string) error {
+	shell := detectShell()
+	if len(args) > 0 {
+		shell = args[0]
+	}
+	if shell == "unknown" {
+		ui.PrintError("Could not detect shell. Please specify one: aka shell-init [bash|zsh|fish]")
+		return fmt.Errorf("unknown shell")
+	}
+
+	code, err := launcher.ShellInit(shell)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to generate shell functions: %v", err))
+		return err
+	}
+
+	fmt.Print(code)
+	return nil
+}
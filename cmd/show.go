@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+// maskedPassword stands in for saved passwords wherever a launcher is displayed
+const maskedPassword = "••••••••"
+
+var showCmd = &cobra.Command{
+	Use:   "show <shortname>",
+	Short: "Show everything about a launcher",
+	Long: `Print every field of a launcher's definition, where its script lives,
+whether the script matches what aka would generate today, how often it has
+been run, and the script itself. Saved passwords are masked.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationStructuredOutput: "true"},
+	RunE:        runShow,
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+	showCmd.Flags().Bool("no-script", false, "Leave out the script")
+}
+
+// showRecord is the structured form of 'aka show'
+type showRecord struct {
+	launcherRecord `yaml:",inline"`
+	Status         string `json:"status" yaml:"status"`
+	ScriptContent  string `json:"script_content,omitempty" yaml:"script_content,omitempty"`
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if owner := launcher.AliasOwner(name); owner != "" {
+		name = owner // an alias shows the launcher it belongs to
+	}
+
+	store, err := launcher.LoadMetadata()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+	meta := store[name]
+
+	path := launcher.ScriptPath(name)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		ui.PrintError(fmt.Sprintf("Failed to read %s: %v", path, err))
+		return err
+	}
+	if meta == nil && data == nil {
+		ui.PrintError(fmt.Sprintf("Launcher '%s' does not exist", name))
+		return withCode(codeNotFound, fmt.Errorf("launcher not found"))
+	}
+	if meta == nil && !launcher.IsManaged(name) {
+		ui.PrintError(fmt.Sprintf("'%s' in %s was not created by aka", name, launcher.GetLauncherDir()))
+		return launcher.ErrNotManaged
+	}
+
+	script := string(data)
+	if meta != nil && meta.SSHConfig != nil && meta.SSHConfig.Password != "" {
+		script = strings.ReplaceAll(script, meta.SSHConfig.Password, maskedPassword)
+	}
+
+	status, err := scriptStatus(name, meta, data != nil)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to compare script: %v", err))
+		return err
+	}
+
+	usage, err := launcher.LoadUsage()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to read usage log: %v", err))
+		return err
+	}
+
+	// Without metadata, List still knows the target from the script
+	var target string
+	if meta == nil {
+		launchers, _ := launcher.List()
+		for _, l := range launchers {
+			if l.Name == name {
+				target, meta = l.Target, l.Metadata
+			}
+		}
+	}
+	record := newLauncherRecord(name, target, meta, usage[name])
+
+	noScript, _ := cmd.Flags().GetBool("no-script")
+	if structuredOutput() {
+		r := showRecord{launcherRecord: record, Status: status}
+		if !noScript {
+			r.ScriptContent = script
+		}
+		return writeRecord(r, func(r showRecord) []string {
+			return append(launcherTSV(r.launcherRecord), r.Status)
+		})
+	}
+
+	fmt.Println()
+	ui.Header(name, record.Description)
+	ui.KeyValue("Type", record.Type)
+	if record.Target != "" {
+		ui.KeyValue("Target", record.Target)
+	}
+	for i, t := range record.Targets {
+		ui.KeyValue(fmt.Sprintf("Target %d", i+1), t)
+	}
+	if meta != nil {
+		for _, action := range launcher.ActionNames(meta) {
+			ui.KeyValue("Action "+action, record.Actions[action])
+		}
+	}
+	if len(record.Tags) > 0 {
+		ui.KeyValue("Tags", strings.Join(record.Tags, ", "))
+	}
+	if len(record.Aliases) > 0 {
+		ui.KeyValue("Aliases", strings.Join(record.Aliases, ", "))
+	}
+
+	keys := make([]string, 0, len(record.Env))
+	for k := range record.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ui.KeyValue("Env "+k, record.Env[k])
+	}
+
+	if ssh := record.SSH; ssh != nil {
+		port := "22"
+		if ssh.Port != 0 {
+			port = fmt.Sprint(ssh.Port)
+		}
+		ui.KeyValue("SSH port", port)
+		if ssh.KeyFile != "" {
+			ui.KeyValue("SSH key", ssh.KeyFile)
+		}
+		if ssh.PasswordSaved {
+			ui.KeyValue("SSH password", maskedPassword)
+		}
+	}
+
+	if meta != nil && !meta.CreatedAt.IsZero() {
+		ui.KeyValue("Created", fmt.Sprintf("%s (%s)", meta.CreatedAt.Local().Format("2006-01-02 15:04"), ui.Ago(meta.CreatedAt)))
+	}
+	if u := usage[name]; u.Count > 0 {
+		ui.KeyValue("Usage", fmt.Sprintf("%d run(s), last %s", u.Count, ui.Ago(u.Last)))
+	} else {
+		ui.KeyValue("Usage", "never run")
+	}
+	ui.KeyValue("Script", path)
+	ui.KeyValue("Status", status)
+
+	if data != nil && !noScript {
+		ui.Section("Script")
+		fmt.Println()
+		ui.Script(script)
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// scriptStatus describes whether the script on disk matches what GenerateScript
+// produces from the metadata now
+func scriptStatus(name string, meta *launcher.LauncherMetadata, exists bool) (string, error) {
+	switch {
+	case meta == nil:
+		return "no metadata (run 'aka doctor --fix')", nil
+	case !exists:
+		return "script missing (run 'aka rebuild " + name + "')", nil
+	}
+
+	items, err := launcher.PlanRebuild([]string{name})
+	if err != nil {
+		return "", err
+	}
+	if len(items) == 0 {
+		return "up to date", nil
+	}
+	return "differs from generator: " + items[0].Reason(), nil
+}
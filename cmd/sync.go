@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync launcher definitions through a git repository",
+	Long: `Share launcher definitions between machines through a git repository.
+
+Only definitions are synced: generated scripts and secrets such as SSH
+passwords stay on each machine. A sync pulls, merges each launcher separately,
+pushes the result and regenerates local scripts that changed upstream.
+
+A launcher changed on both sides since the last sync is reported as a
+conflict and left alone; resolve it with --ours or --theirs. Settings that
+must differ per machine belong in 'aka sync override'.`,
+	Example: `  git init --bare ~/launchers.git
+  aka sync init ~/launchers.git
+  aka sync`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationDryRun: "true"},
+	RunE:        runSync,
+}
+
+var syncInitCmd = &cobra.Command{
+	Use:   "init <repo>",
+	Short: "Set up the sync repository",
+	Long:  `Clone a git repository (a local path or URL) to sync launchers through.`,
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSyncInit,
+}
+
+var syncOverrideCmd = &cobra.Command{
+	Use:   "override <shortname>",
+	Short: "Set machine-specific settings for a synced launcher",
+	Long: `Keep settings that only apply to this machine out of the sync repository.
+
+Overridden values are used locally and are never pushed; the shared value is
+kept in the repository. --exclude stops syncing the launcher altogether.`,
+	Example: `  aka sync override prod --key ~/.ssh/laptop_ed25519
+  aka sync override dev --env DISPLAY=:1
+  aka sync override scratch --exclude`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSyncOverride,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncInitCmd)
+	syncCmd.AddCommand(syncOverrideCmd)
+	syncCmd.Flags().Bool("ours", false, "Resolve conflicts with this machine's version")
+	syncCmd.Flags().Bool("theirs", false, "Resolve conflicts with the repository's version")
+	syncOverrideCmd.Flags().StringToString("env", nil, "Environment variables for this machine (key=value)")
+	syncOverrideCmd.Flags().Int("port", 0, "SSH port for this machine")
+	syncOverrideCmd.Flags().StringP("key", "k", "", "SSH key file for this machine")
+	syncOverrideCmd.Flags().Bool("exclude", false, "Never sync this launcher")
+	syncOverrideCmd.Flags().Bool("clear", false, "Remove all overrides for this launcher")
+}
+
+func runSyncInit(cmd *cobra.Command, args []string) error {
+	state, err := launcher.InitSync(args[0])
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to set up sync: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Syncing with %s (%s)", state.Repo, state.Branch))
+	ui.PrintExample("Run the first sync:", "aka sync")
+	fmt.Println()
+
+	return nil
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	ours, _ := cmd.Flags().GetBool("ours")
+	theirs, _ := cmd.Flags().GetBool("theirs")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if ours && theirs {
+		ui.PrintError("Use either --ours or --theirs, not both.")
+		return fmt.Errorf("conflicting flags")
+	}
+
+	strategy := launcher.ConflictReport
+	if ours {
+		strategy = launcher.ConflictOurs
+	} else if theirs {
+		strategy = launcher.ConflictTheirs
+	}
+
+	results, err := launcher.Sync(strategy, dryRun)
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Sync failed: %v", err))
+		return err
+	}
+
+	var rows [][]string
+	conflicts := 0
+	for _, r := range results {
+		if r.Outcome == launcher.SyncUnchanged {
+			continue
+		}
+		if r.Outcome == launcher.SyncConflict {
+			conflicts++
+		}
+		rows = append(rows, []string{r.Name, string(r.Outcome)})
+	}
+
+	fmt.Println()
+	if len(rows) > 0 {
+		ui.Table([]string{"Launcher", "Result"}, rows)
+		fmt.Println()
+	}
+
+	switch {
+	case dryRun:
+		ui.PrintInfo("Dry run: nothing was pushed or changed locally.")
+	case len(rows) == 0:
+		ui.SuccessBox("Everything is in sync")
+	default:
+		ui.SuccessBox(fmt.Sprintf("Synced %d launcher(s)", len(results)))
+	}
+
+	if conflicts > 0 {
+		ui.PrintWarning(fmt.Sprintf("%d launcher(s) changed on both sides and were left alone.", conflicts))
+		ui.PrintExample("Keep this machine's version:", "aka sync --ours")
+		ui.PrintExample("Take the repository's version:", "aka sync --theirs")
+	}
+	fmt.Println()
+
+	return nil
+}
+
+func runSyncOverride(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	overrides, err := launcher.LoadSyncOverrides()
+	if err != nil {
+		ui.PrintError(err.Error())
+		return err
+	}
+
+	if clear, _ := cmd.Flags().GetBool("clear"); clear {
+		delete(overrides, name)
+	} else {
+		o := overrides[name]
+		if o == nil {
+			o = &launcher.SyncOverride{}
+			overrides[name] = o
+		}
+
+		if exclude, _ := cmd.Flags().GetBool("exclude"); exclude {
+			o.Exclude = true
+		}
+		if env, _ := cmd.Flags().GetStringToString("env"); len(env) > 0 {
+			if o.Env == nil {
+				o.Env = make(map[string]string)
+			}
+			for k, v := range env {
+				o.Env[k] = v
+			}
+		}
+		port, _ := cmd.Flags().GetInt("port")
+		key, _ := cmd.Flags().GetString("key")
+		if port != 0 || key != "" {
+			if o.SSHConfig == nil {
+				o.SSHConfig = &launcher.SSHConfig{}
+			}
+			if port != 0 {
+				o.SSHConfig.Port = port
+			}
+			if key != "" {
+				o.SSHConfig.KeyFile = key
+			}
+		}
+	}
+
+	if err := launcher.SaveSyncOverrides(overrides); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to save overrides: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Updated overrides for '%s'", name))
+	ui.PrintInfo("Overrides take effect on the next 'aka sync'.")
+	fmt.Println()
+
+	return nil
+}
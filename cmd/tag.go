@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage launcher tags",
+	Long: `Group launchers with tags. Tags select launchers in 'aka list --tag',
+'aka remove --tag' and 'aka export --tag'.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <shortname> <tag>...",
+	Short: "Add tags to a launcher",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runTagAdd,
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:     "remove <shortname> <tag>...",
+	Aliases: []string{"rm"},
+	Short:   "Remove tags from a launcher",
+	Args:    cobra.MinimumNArgs(2),
+	RunE:    runTagRemove,
+}
+
+var tagListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List tags and how many launchers carry each",
+	Args:    cobra.NoArgs,
+	RunE:    runTagList,
+}
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <shortname> [description]",
+	Short: "Set or clear a launcher's description",
+	Long: `Set the description shown by 'aka list'. Leave the description out to
+clear it.`,
+	Example: `  aka describe prod "Production API host"`,
+	Args:    cobra.RangeArgs(1, 2),
+	RunE:    runDescribe,
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	tagCmd.AddCommand(tagListCmd)
+	rootCmd.AddCommand(describeCmd)
+}
+
+func runTagAdd(cmd *cobra.Command, args []string) error {
+	name, tags := args[0], launcher.NormalizeTags(splitTags(args[1:]))
+
+	err := launcher.Update(name, func(meta *launcher.LauncherMetadata) error {
+		meta.Tags = append(meta.Tags, tags...)
+		return nil
+	})
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to tag '%s': %v", name, err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Tagged '%s' with %s", name, strings.Join(tags, ", ")))
+	fmt.Println()
+	return nil
+}
+
+func runTagRemove(cmd *cobra.Command, args []string) error {
+	name, tags := args[0], launcher.NormalizeTags(splitTags(args[1:]))
+
+	err := launcher.Update(name, func(meta *launcher.LauncherMetadata) error {
+		var kept []string
+		for _, tag := range meta.Tags {
+			if !containsString(tags, tag) {
+				kept = append(kept, tag)
+			}
+		}
+		meta.Tags = kept
+		return nil
+	})
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to untag '%s': %v", name, err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Removed %s from '%s'", strings.Join(tags, ", "), name))
+	fmt.Println()
+	return nil
+}
+
+func runTagList(cmd *cobra.Command, args []string) error {
+	store, err := launcher.LoadMetadata()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to load metadata: %v", err))
+		return err
+	}
+
+	counts := make(map[string]int)
+	for _, meta := range store {
+		if meta == nil {
+			continue
+		}
+		for _, tag := range meta.Tags {
+			counts[tag]++
+		}
+	}
+
+	fmt.Println()
+	if len(counts) == 0 {
+		ui.PrintInfo("No launchers are tagged yet.")
+		ui.PrintExample("Tag one:", "aka tag add <shortname> <tag>")
+		fmt.Println()
+		return nil
+	}
+
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	rows := make([][]string, len(tags))
+	for i, tag := range tags {
+		rows[i] = []string{tag, fmt.Sprintf("%d", counts[tag])}
+	}
+	ui.Table([]string{"Tag", "Launchers"}, rows)
+	fmt.Println()
+	return nil
+}
+
+func runDescribe(cmd *cobra.Command, args []string) error {
+	name, description := args[0], ""
+	if len(args) > 1 {
+		description = args[1]
+	}
+
+	err := launcher.Update(name, func(meta *launcher.LauncherMetadata) error {
+		meta.Description = description
+		return nil
+	})
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to update '%s': %v", name, err))
+		return err
+	}
+
+	fmt.Println()
+	if description == "" {
+		ui.SuccessBox(fmt.Sprintf("Cleared the description of '%s'", name))
+	} else {
+		ui.SuccessBox(fmt.Sprintf("Updated the description of '%s'", name))
+	}
+	fmt.Println()
+	return nil
+}
+
+// splitTags accepts tags as separate arguments or comma-separated lists
+func splitTags(args []string) []string {
+	var tags []string
+	for _, arg := range args {
+		tags = append(tags, strings.Split(arg, ",")...)
+	}
+	return tags
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
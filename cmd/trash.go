@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage removed launchers",
+	Long: `Removed launchers are kept in the trash until you empty it, so they can be
+restored with their definition and script.`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List removed launchers",
+	Args:    cobra.NoArgs,
+	RunE:    runTrashList,
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <shortname|id>",
+	Short: "Bring a removed launcher back",
+	Long: `Restore a launcher from the trash. Given a name, the most recently removed
+launcher with that name is restored; use the ID from 'aka trash list' to pick
+an older one.`,
+	Example: `  aka trash restore prod
+  aka trash restore 12 --as prod-old`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrashRestore,
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Delete removed launchers for good",
+	Args:  cobra.NoArgs,
+	RunE:  runTrashEmpty,
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+	trashRestoreCmd.Flags().String("as", "", "Restore under a different name")
+	trashEmptyCmd.Flags().BoolP("force", "f", false, "Empty without confirmation")
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	items, err := launcher.ListTrash()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to read the trash: %v", err))
+		return err
+	}
+
+	if len(items) == 0 {
+		ui.PrintInfo("The trash is empty.")
+		return nil
+	}
+
+	var rows [][]string
+	for _, item := range items {
+		typ, target := "", ""
+		if meta := item.Metadata; meta != nil {
+			typ, target = string(meta.Type), meta.Target
+			if meta.Type == launcher.TypeStack {
+				target = fmt.Sprintf("%d targets", len(meta.Targets))
+			}
+			if meta.Type == launcher.TypeActions {
+				target = fmt.Sprintf("%d actions", len(meta.Actions))
+			}
+		}
+		rows = append(rows, []string{strconv.Itoa(item.ID), item.Name, typ, truncate(target, 40), ui.Ago(item.RemovedAt)})
+	}
+
+	fmt.Println()
+	ui.Table([]string{"ID", "Name", "Type", "Target", "Removed"}, rows)
+	fmt.Println()
+	ui.PrintExample("Restore one:", "aka trash restore <name|id>")
+	fmt.Println()
+	return nil
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) error {
+	item, err := launcher.FindTrash(args[0])
+	if err != nil {
+		ui.PrintError(err.Error())
+		return err
+	}
+
+	as, _ := cmd.Flags().GetString("as")
+	if as != "" && !isValidShortname(as) {
+		ui.PrintError("Invalid name. Use only alphanumeric characters, hyphens, and underscores.")
+		return fmt.Errorf("invalid shortname")
+	}
+
+	if err := launcher.Restore(item, as); err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to restore '%s': %v", item.Name, err))
+		return err
+	}
+
+	if as == "" {
+		as = item.Name
+	}
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Restored launcher '%s'", as))
+	fmt.Println()
+	return nil
+}
+
+func runTrashEmpty(cmd *cobra.Command, args []string) error {
+	items, err := launcher.ListTrash()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to read the trash: %v", err))
+		return err
+	}
+	if len(items) == 0 {
+		ui.PrintInfo("The trash is already empty.")
+		return nil
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force && !ui.Confirm(fmt.Sprintf("Delete %d removed launchers for good?", len(items))) {
+		ui.PrintInfo("Cancelled.")
+		return nil
+	}
+
+	n, err := launcher.EmptyTrash()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to empty the trash: %v", err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Emptied the trash (%d launchers)", n))
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dorochadev/aka/launcher"
+	"github.com/dorochadev/aka/ui"
+	"github.com/spf13/cobra"
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last change to your launchers",
+	Long: `Revert the most recent add, change, rename, removal or restore recorded in
+'aka history'. Running it again walks further back. A removed launcher comes
+back from the trash.`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	undoCmd.Flags().BoolP("force", "f", false, "Undo without confirmation")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	last, err := launcher.LastUndoable()
+	if errors.Is(err, launcher.ErrNothingToUndo) {
+		ui.PrintInfo("Nothing to undo.")
+		return nil
+	}
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to read history: %v", err))
+		return err
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force {
+		fmt.Println()
+		ui.KeyValue("Change", fmt.Sprintf("#%d %s", last.ID, last.Op))
+		ui.KeyValue("Launcher", last.Name)
+		ui.KeyValue("When", ui.Ago(last.Time))
+		ui.KeyValue("What", last.Summary())
+		fmt.Println()
+		if !ui.Confirm("Undo this change?") {
+			ui.PrintInfo("Cancelled.")
+			return nil
+		}
+	}
+
+	undone, err := launcher.Undo()
+	if err != nil {
+		ui.PrintError(fmt.Sprintf("Failed to undo #%d: %v", last.ID, err))
+		return err
+	}
+
+	fmt.Println()
+	ui.SuccessBox(fmt.Sprintf("Undid #%d: %s %s", undone.ID, undone.Name, undone.Summary()))
+	fmt.Println()
+	return nil
+}
@@ -6,6 +6,7 @@ require (
 	github.com/gookit/color v1.6.0
 	github.com/spf13/cobra v1.8.0
 	golang.org/x/term v0.40.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,157 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Aliases are extra names for a launcher. Each one is a symlink in the
+// launcher directory pointing at the launcher's script, so aliases share its
+// definition and never drift apart. They are recorded in the launcher's
+// metadata and kept in step by Create, Remove and Rename.
+
+// isAliasLink reports whether the file at name is a symlink to another
+// launcher script in the same directory, and returns that launcher's name
+func isAliasLink(name string) (string, bool) {
+	target, err := os.Readlink(launcherPath(name))
+	if err != nil || !ValidName(target) {
+		return "", false
+	}
+	return target, true
+}
+
+// AliasOwner returns the launcher that alias belongs to, or "" if it is not an alias
+func AliasOwner(alias string) string {
+	store, err := LoadMetadata()
+	if err != nil {
+		return ""
+	}
+	return store.aliasOwner(alias)
+}
+
+func (store MetadataStore) aliasOwner(alias string) string {
+	for name, meta := range store {
+		if meta != nil && containsName(meta.Aliases, alias) {
+			return name
+		}
+	}
+	return ""
+}
+
+// ensureNotAlias refuses to treat an alias as a launcher of its own
+func ensureNotAlias(name string) error {
+	if owner := AliasOwner(name); owner != "" {
+		return fmt.Errorf("'%s' is an alias of '%s' (remove it with 'aka alias remove %s')", name, owner, name)
+	}
+	return nil
+}
+
+// AddAliases gives a launcher more names
+func AddAliases(name string, aliases []string) error {
+	return Update(name, func(meta *LauncherMetadata) error {
+		for _, alias := range aliases {
+			if containsName(meta.Aliases, alias) {
+				return fmt.Errorf("'%s' is already an alias", alias)
+			}
+			meta.Aliases = append(meta.Aliases, alias)
+		}
+		return nil
+	})
+}
+
+// RemoveAlias takes an alias away from the launcher it belongs to and
+// returns that launcher's name
+func RemoveAlias(alias string) (string, error) {
+	owner := AliasOwner(alias)
+	if owner == "" {
+		return "", fmt.Errorf("'%s' is not an alias", alias)
+	}
+	return owner, Update(owner, func(meta *LauncherMetadata) error {
+		var kept []string
+		for _, a := range meta.Aliases {
+			if a != alias {
+				kept = append(kept, a)
+			}
+		}
+		meta.Aliases = kept
+		return nil
+	})
+}
+
+// checkAliases makes sure every alias of a launcher is free to use: either
+// unused or already a link to this launcher
+func checkAliases(name string, meta *LauncherMetadata) error {
+	store, err := LoadMetadata()
+	if err != nil {
+		return err
+	}
+	for _, alias := range meta.Aliases {
+		if owner := store.aliasOwner(alias); owner != "" && owner != name {
+			return fmt.Errorf("'%s' is already an alias of '%s'", alias, owner)
+		}
+		if target, ok := isAliasLink(alias); ok && target == name {
+			continue
+		}
+		if Exists(alias) || store[alias] != nil {
+			return fmt.Errorf("alias '%s' is taken by another launcher or file", alias)
+		}
+	}
+	return nil
+}
+
+// linkAliases creates the symlinks for meta's aliases and removes those of
+// aliases in before that were dropped
+func linkAliases(name string, before, meta *LauncherMetadata) error {
+	if before != nil {
+		for _, alias := range before.Aliases {
+			if meta == nil || !containsName(meta.Aliases, alias) {
+				unlinkAlias(alias)
+			}
+		}
+	}
+	if meta == nil {
+		return nil
+	}
+	for _, alias := range meta.Aliases {
+		if target, ok := isAliasLink(alias); ok && target == name {
+			continue
+		}
+		unlinkAlias(alias)
+		if err := os.Symlink(name, launcherPath(alias)); err != nil {
+			return fmt.Errorf("failed to create alias '%s': %w", alias, err)
+		}
+	}
+	return nil
+}
+
+// unlinkAlias removes an alias symlink, leaving anything else alone
+func unlinkAlias(alias string) {
+	if _, ok := isAliasLink(alias); ok {
+		_ = os.Remove(launcherPath(alias))
+	}
+}
+
+// NormalizeAliases sorts aliases and drops duplicates
+func NormalizeAliases(aliases []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, alias := range aliases {
+		if alias == "" || seen[alias] {
+			continue
+		}
+		seen[alias] = true
+		normalized = append(normalized, alias)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package launcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BundleVersion is the format version of export bundles
+const BundleVersion = 1
+
+// Bundle is a portable set of launcher definitions for handing to someone else
+type Bundle struct {
+	Format        int           `json:"aka_bundle"`
+	SchemaVersion int           `json:"schema_version"`
+	Launchers     MetadataStore `json:"launchers"`
+	// PasswordRequired names SSH launchers whose saved password was stripped
+	// on export and should be asked for again on import
+	PasswordRequired []string `json:"password_required,omitempty"`
+}
+
+// NewBundle builds a bundle from the given launchers, removing secrets
+func NewBundle(store MetadataStore) *Bundle {
+	bundle := &Bundle{
+		Format:        BundleVersion,
+		SchemaVersion: SchemaVersion,
+		Launchers:     make(MetadataStore, len(store)),
+	}
+	for name, meta := range store {
+		if meta.SSHConfig != nil && meta.SSHConfig.Password != "" {
+			bundle.PasswordRequired = append(bundle.PasswordRequired, name)
+		}
+		public := publicMetadata(meta)
+		public.CreatedAt = time.Time{} // the importer's launchers are new to them
+		bundle.Launchers[name] = public
+	}
+	sort.Strings(bundle.PasswordRequired)
+	return bundle
+}
+
+// Marshal renders the bundle as indented JSON
+func (b *Bundle) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// NeedsPassword reports whether the named launcher had a password before export
+func (b *Bundle) NeedsPassword(name string) bool {
+	for _, n := range b.PasswordRequired {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadBundle reads and validates a bundle. Declarative aka.yaml files are
+// accepted too, so either format can be imported.
+func LoadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		spec, err := LoadSpec(path)
+		if err != nil {
+			return nil, err
+		}
+		return &Bundle{Format: BundleVersion, SchemaVersion: SchemaVersion, Launchers: spec.Launchers}, nil
+	}
+
+	var header struct {
+		Format        int            `json:"aka_bundle"`
+		SchemaVersion int            `json:"schema_version"`
+		Launchers     map[string]any `json:"launchers"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if header.Format == 0 {
+		return nil, fmt.Errorf("%s is not an aka bundle", path)
+	}
+	if header.Format > BundleVersion {
+		return nil, fmt.Errorf("%s uses bundle format %d, newer than this version of aka supports (%d)", path, header.Format, BundleVersion)
+	}
+	if header.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("%s uses metadata schema %d, newer than this version of aka supports (%d)", path, header.SchemaVersion, SchemaVersion)
+	}
+
+	// Bundles from older releases go through the same migrations as launchers.json
+	if header.SchemaVersion < SchemaVersion {
+		doc := map[string]any{"version": float64(header.SchemaVersion), "launchers": header.Launchers}
+		if header.Launchers == nil {
+			doc["launchers"] = map[string]any{}
+		}
+		if _, err := migrateDocument(doc, header.SchemaVersion); err != nil {
+			return nil, fmt.Errorf("failed to migrate %s: %w", path, err)
+		}
+		migrated, err := json.Marshal(doc["launchers"])
+		if err != nil {
+			return nil, err
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		raw["launchers"] = migrated
+		if data, err = json.Marshal(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	var bundle Bundle
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("invalid bundle %s: %w", path, err)
+	}
+	bundle.SchemaVersion = SchemaVersion
+	if bundle.Launchers == nil {
+		bundle.Launchers = make(MetadataStore)
+	}
+
+	for name, meta := range bundle.Launchers {
+		if meta == nil {
+			return nil, fmt.Errorf("%s: definition is empty", name)
+		}
+		if meta.SSHConfig != nil && meta.SSHConfig.Password != "" {
+			return nil, fmt.Errorf("%s: bundles must not contain passwords", name)
+		}
+		Normalize(meta)
+		if err := Validate(name, meta); err != nil {
+			return nil, err
+		}
+	}
+
+	return &bundle, nil
+}
+
+// Names returns the bundle's launcher names in order
+func (b *Bundle) Names() []string {
+	names := make([]string, 0, len(b.Launchers))
+	for name := range b.Launchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AvailableName returns name, or name-2, name-3, ... if it is already taken
+func AvailableName(name string) string {
+	if !Exists(name) {
+		if meta, _ := GetMetadata(name); meta == nil {
+			return name
+		}
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", name, i)
+		if !Exists(candidate) {
+			if meta, _ := GetMetadata(candidate); meta == nil {
+				return candidate
+			}
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// IssueKind classifies a problem found by Diagnose
+type IssueKind string
+
+const (
+	IssueMissingMetadata   IssueKind = "missing-metadata"
+	IssueMissingScript     IssueKind = "missing-script"
+	IssueOutdatedScript    IssueKind = "outdated-script"
+	IssueEditedScript      IssueKind = "edited-script"
+	IssueMissingDependency IssueKind = "missing-dependency"
+	IssueShadowed          IssueKind = "shadowed"
+	IssueBrokenAlias       IssueKind = "broken-alias"
+)
+
+// Issue is a single drift or dependency problem for one launcher
+type Issue struct {
+	Kind   IssueKind
+	Name   string
+	Detail string
+}
+
+// Fixable reports whether Fix can resolve the issue automatically
+func (i Issue) Fixable() bool {
+	return i.Kind != IssueMissingDependency && i.Kind != IssueEditedScript && i.Kind != IssueShadowed
+}
+
+// FixDescription explains what Fix would do
+func (i Issue) FixDescription() string {
+	switch i.Kind {
+	case IssueMissingMetadata:
+		return fmt.Sprintf("Adopt '%s' into launchers.json", i.Name)
+	case IssueMissingScript:
+		return fmt.Sprintf("Prune '%s' from launchers.json", i.Name)
+	case IssueOutdatedScript:
+		return fmt.Sprintf("Regenerate the script for '%s'", i.Name)
+	case IssueBrokenAlias:
+		return fmt.Sprintf("Relink the aliases of '%s'", i.Name)
+	default:
+		return ""
+	}
+}
+
+// Fix resolves the issue by adopting, pruning or regenerating the launcher
+func (i Issue) Fix() error {
+	switch i.Kind {
+	case IssueMissingMetadata:
+		return Adopt(i.Name)
+	case IssueMissingScript:
+		return DeleteMetadata(i.Name)
+	case IssueOutdatedScript:
+		return Regenerate(i.Name)
+	case IssueBrokenAlias:
+		meta, err := GetMetadata(i.Name)
+		if err != nil {
+			return err
+		}
+		return linkAliases(i.Name, nil, meta)
+	default:
+		return fmt.Errorf("%s cannot be fixed automatically", i.Kind)
+	}
+}
+
+// Regenerate rewrites a launcher's script from its stored metadata
+func Regenerate(name string) error {
+	metadata, err := GetMetadata(name)
+	if err != nil {
+		return err
+	}
+	if metadata == nil {
+		return fmt.Errorf("launcher '%s' has no metadata", name)
+	}
+	return Create(name, metadata)
+}
+
+// Diagnose compares the launcher directory with the metadata index and checks
+// that every launcher's runtime dependencies are installed
+func Diagnose() ([]Issue, error) {
+	names, err := readLauncherDir()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := LoadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := GetLauncherDir()
+	onDisk := make(map[string]bool, len(names))
+	var issues []Issue
+
+	for _, name := range names {
+		onDisk[name] = true
+		path := filepath.Join(dir, name)
+
+		meta, ok := store[name]
+		if !ok || meta == nil {
+			if hasMarker(path) {
+				detail := "script exists but launchers.json has no entry for it"
+				if embedded, _ := ReadScriptMetadata(path); embedded != nil {
+					detail = "script is not in launchers.json but carries its own metadata"
+				}
+				issues = append(issues, Issue{
+					Kind:   IssueMissingMetadata,
+					Name:   name,
+					Detail: detail,
+				})
+			}
+			continue
+		}
+
+		// Adopted hand-written files have no marker and are never regenerated
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read launcher '%s': %w", name, err)
+		}
+		if hasMarker(path) && string(content) != GenerateScript(meta.Target, meta) {
+			if IsHandEdited(string(content)) {
+				issues = append(issues, Issue{
+					Kind:   IssueEditedScript,
+					Name:   name,
+					Detail: "script was edited by hand; 'aka rebuild --discard-edits' replaces it",
+				})
+			} else {
+				issues = append(issues, Issue{
+					Kind:   IssueOutdatedScript,
+					Name:   name,
+					Detail: "script differs from what aka would generate today",
+				})
+			}
+		}
+
+		for _, dep := range MissingDependencies(meta) {
+			issues = append(issues, Issue{
+				Kind:   IssueMissingDependency,
+				Name:   name,
+				Detail: dep,
+			})
+		}
+
+		for _, alias := range meta.Aliases {
+			if target, ok := isAliasLink(alias); !ok || target != name {
+				issues = append(issues, Issue{
+					Kind:   IssueBrokenAlias,
+					Name:   name,
+					Detail: fmt.Sprintf("alias '%s' is missing or does not link to the launcher", alias),
+				})
+			}
+		}
+
+		if shadow := FindShadow(name); shadow != nil {
+			issues = append(issues, Issue{
+				Kind:   IssueShadowed,
+				Name:   name,
+				Detail: shadow.Detail(),
+			})
+		}
+	}
+
+	for name := range store {
+		if !onDisk[name] {
+			issues = append(issues, Issue{
+				Kind:   IssueMissingScript,
+				Name:   name,
+				Detail: "launchers.json has an entry but the script is gone",
+			})
+		}
+	}
+
+	sort.SliceStable(issues, func(a, b int) bool {
+		if issues[a].Name != issues[b].Name {
+			return issues[a].Name < issues[b].Name
+		}
+		return issues[a].Kind < issues[b].Kind
+	})
+
+	return issues, nil
+}
+
+// MissingDependencies lists the external programs or applications a launcher
+// needs at runtime that cannot be found on this machine
+func MissingDependencies(meta *LauncherMetadata) []string {
+	var missing []string
+	seen := make(map[string]bool)
+	add := func(msg string) {
+		if !seen[msg] {
+			seen[msg] = true
+			missing = append(missing, msg)
+		}
+	}
+
+	check := func(typ LauncherType, target string) {
+		switch typ {
+		case TypeURL:
+			if opener := urlOpener(); opener != "" && !onPath(opener) {
+				add(fmt.Sprintf("'%s' is not installed", opener))
+			}
+		case TypeSSH:
+			if !onPath("ssh") {
+				add("'ssh' is not installed")
+			}
+		case TypeCommand:
+			if bin := commandBinary(target); bin != "" && !onPath(bin) {
+				add(fmt.Sprintf("'%s' was not found on PATH", bin))
+			}
+		case TypeDirectory:
+			if info, err := os.Stat(expandHome(target)); err != nil || !info.IsDir() {
+				add(fmt.Sprintf("directory '%s' does not exist", target))
+			}
+		case TypeApplication:
+			if opener := urlOpener(); opener != "" && !onPath(opener) {
+				add(fmt.Sprintf("'%s' is not installed", opener))
+			} else if !appInstalled(target) {
+				add(fmt.Sprintf("application '%s' could not be found", target))
+			}
+		}
+	}
+
+	if meta.Type == TypeStack {
+		for _, t := range meta.Targets {
+			check(DetectLauncherType(t), t)
+		}
+	} else if meta.Type == TypeActions {
+		for _, name := range ActionNames(meta) {
+			t := meta.Actions[name]
+			check(DetectLauncherType(t), t)
+		}
+	} else {
+		check(meta.Type, meta.Target)
+	}
+
+	if meta.Type == TypeSSH && meta.SSHConfig != nil && meta.SSHConfig.Password != "" && !onPath("sshpass") {
+		add("'sshpass' is required for saved passwords but is not installed")
+	}
+
+	return missing
+}
+
+// urlOpener returns the program used to open URLs and applications on this platform
+func urlOpener() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "open"
+	case "linux":
+		return "xdg-open"
+	default:
+		return ""
+	}
+}
+
+func onPath(bin string) bool {
+	_, err := exec.LookPath(bin)
+	return err == nil
+}
+
+// shellBuiltins are commands that never appear on PATH
+var shellBuiltins = map[string]bool{
+	"cd": true, "echo": true, "export": true, "source": true, ".": true,
+	"exec": true, "eval": true, "set": true, "unset": true, "alias": true,
+	"printf": true, "test": true, "[": true, "true": true, "false": true,
+	"exit": true, "read": true, "pushd": true, "popd": true, "ulimit": true,
+	"umask": true, "wait": true, "trap": true, "shift": true, "type": true,
+}
+
+// commandBinary returns the program a shell command starts with, or "" if it
+// begins with a builtin, an assignment or something too complex to resolve
+func commandBinary(command string) string {
+	fields := strings.Fields(command)
+	for _, f := range fields {
+		if strings.Contains(f, "=") {
+			continue // leading VAR=value assignment
+		}
+		if f == "sudo" || f == "env" || f == "nohup" || f == "time" {
+			continue
+		}
+		if shellBuiltins[f] || strings.ContainsAny(f, "$`(){}\"'") {
+			return ""
+		}
+		return expandHome(f)
+	}
+	return ""
+}
+
+// expandHome resolves a leading ~ to the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// appInstalled checks the usual application locations for a GUI app
+func appInstalled(name string) bool {
+	switch runtime.GOOS {
+	case "darwin":
+		home, _ := os.UserHomeDir()
+		for _, dir := range []string{"/Applications", "/System/Applications", "/Applications/Utilities", filepath.Join(home, "Applications")} {
+			if _, err := os.Stat(filepath.Join(dir, name+".app")); err == nil {
+				return true
+			}
+		}
+		return false
+	case "linux":
+		if onPath(name) || onPath(strings.ToLower(strings.ReplaceAll(name, " ", "-"))) {
+			return true
+		}
+		// Files and URLs handed to xdg-open are resolved by the desktop, not PATH
+		if _, err := os.Stat(name); err == nil {
+			return true
+		}
+		return desktopEntryExists(name)
+	default:
+		return true
+	}
+}
+
+// desktopEntryExists looks for a .desktop file matching name on Linux
+func desktopEntryExists(name string) bool {
+	want := strings.ToLower(strings.ReplaceAll(name, " ", "-")) + ".desktop"
+
+	dirs := []string{"/usr/share/applications", "/usr/local/share/applications"}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".local", "share", "applications"))
+	}
+
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if strings.ToLower(entry.Name()) == want {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package launcher
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// editErrorPrefix starts the comment lines that report problems in an edited document
+const editErrorPrefix = "# error: "
+
+var (
+	yamlLinePattern = regexp.MustCompile(`line (\d+):`)
+	yamlTypePattern = regexp.MustCompile(` in type \S+`)
+)
+
+// EditError is a problem with an edited document, at Line when it is known
+type EditError struct {
+	Line int
+	Err  error
+}
+
+func (e *EditError) Error() string { return e.Err.Error() }
+func (e *EditError) Unwrap() error { return e.Err }
+
+// EditDocument renders a launcher's definition as YAML for editing by hand
+func EditDocument(name string, meta *LauncherMetadata) ([]byte, error) {
+	public := publicMetadata(meta)
+	data, err := yaml.Marshal(public)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Editing launcher '%s'. Save and close to apply; delete everything to cancel.\n", name)
+	b.WriteString("# Fields: type, target, targets (stack), actions, env, ssh_config (port, key_file), description, tags, aliases\n")
+	if meta.SSHConfig != nil && meta.SSHConfig.Password != "" {
+		b.WriteString("# The saved SSH password is kept and not shown here.\n")
+	}
+	b.Write(data)
+	return b.Bytes(), nil
+}
+
+// ParseEditDocument reads an edited document back into a definition and
+// validates it. Secrets and the creation time carry over from current.
+func ParseEditDocument(name string, data []byte, current *LauncherMetadata) (*LauncherMetadata, error) {
+	var meta LauncherMetadata
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&meta); err != nil {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			err = errors.New(yamlTypePattern.ReplaceAllString(strings.Join(typeErr.Errors, "; "), ""))
+		}
+		editErr := &EditError{Err: err}
+		if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			editErr.Line, _ = strconv.Atoi(m[1])
+		}
+		return nil, editErr
+	}
+
+	Normalize(&meta)
+	if err := Validate(name, &meta); err != nil {
+		return nil, &EditError{Err: err}
+	}
+
+	merged := withLocalSecrets(&meta, current)
+	merged.CreatedAt = current.CreatedAt
+	return merged, nil
+}
+
+// StripEditErrors removes error comments added by AnnotateEditError
+func StripEditErrors(data []byte) []byte {
+	var kept []string
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if !strings.HasPrefix(line, editErrorPrefix) {
+			kept = append(kept, line)
+		}
+	}
+	return []byte(strings.Join(kept, ""))
+}
+
+// AnnotateEditError puts err into the document as a comment, right above the
+// offending line when it is known and at the top otherwise
+func AnnotateEditError(data []byte, err error) []byte {
+	lines := strings.SplitAfter(string(data), "\n")
+	comment := editErrorPrefix + strings.ReplaceAll(err.Error(), "\n", " ") + "\n"
+
+	at := 0
+	var editErr *EditError
+	if errors.As(err, &editErr) && editErr.Line > 0 && editErr.Line <= len(lines) {
+		at = editErr.Line - 1
+	}
+
+	annotated := append([]string{}, lines[:at]...)
+	annotated = append(annotated, comment)
+	annotated = append(annotated, lines[at:]...)
+	return []byte(strings.Join(annotated, ""))
+}
+
+// WriteEditedScript replaces a launcher's script with content written by
+// hand. The stamp of the current script is kept, so the content no longer
+// matches its hash and rebuilds leave the script alone.
+func WriteEditedScript(name, content string) error {
+	if err := ensureManaged(name); err != nil {
+		return err
+	}
+
+	path := launcherPath(name)
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read launcher: %w", err)
+	}
+
+	if _, ok := ReadStamp(content); !ok {
+		stampLine := ManagedMarker
+		for _, line := range strings.Split(string(current), "\n") {
+			if strings.HasPrefix(line, ManagedMarker) {
+				stampLine = line
+				break
+			}
+		}
+		lines := strings.SplitAfter(content, "\n")
+		at := 0
+		if len(lines) > 0 && strings.HasPrefix(lines[0], "#!") {
+			at = 1
+		}
+		withStamp := append([]string{}, lines[:at]...)
+		withStamp = append(withStamp, stampLine+"\n")
+		content = strings.Join(append(withStamp, lines[at:]...), "")
+	}
+
+	return os.WriteFile(path, []byte(content), 0755)
+}
@@ -0,0 +1,92 @@
+package launcher
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// metaPrefix starts the comment line holding a launcher's metadata as JSON
+const metaPrefix = "# aka:meta "
+
+// publicMetadata returns a copy of metadata with secrets removed, safe to
+// write into scripts, exports and shared files
+func publicMetadata(metadata *LauncherMetadata) *LauncherMetadata {
+	public := *metadata
+	if metadata.SSHConfig != nil {
+		ssh := *metadata.SSHConfig
+		ssh.Password = ""
+		public.SSHConfig = &ssh
+	}
+	return &public
+}
+
+// embedMetadata adds the metadata comment right after the marker line
+func embedMetadata(script string, metadata *LauncherMetadata) string {
+	data, err := json.Marshal(publicMetadata(metadata))
+	if err != nil {
+		return script
+	}
+	return strings.Replace(script, ManagedMarker+"\n", ManagedMarker+"\n"+metaPrefix+string(data)+"\n", 1)
+}
+
+// ReadScriptMetadata returns the metadata embedded in a launcher script, or
+// nil if the script has none. Secrets such as SSH passwords are never embedded.
+func ReadScriptMetadata(path string) (*LauncherMetadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for i := 0; i < markerScanLines && scanner.Scan(); i++ {
+		data, ok := strings.CutPrefix(scanner.Text(), metaPrefix)
+		if !ok {
+			continue
+		}
+		var metadata LauncherMetadata
+		if err := json.Unmarshal([]byte(data), &metadata); err != nil {
+			return nil, fmt.Errorf("invalid embedded metadata: %w", err)
+		}
+		return &metadata, nil
+	}
+	return nil, scanner.Err()
+}
+
+// RebuildIndex adds every managed script that carries embedded metadata but
+// is missing from launchers.json to the index, returning the names added
+func RebuildIndex() ([]string, error) {
+	names, err := readLauncherDir()
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := LoadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	var added []string
+	for _, name := range names {
+		if _, ok := store[name]; ok {
+			continue
+		}
+		meta, err := ReadScriptMetadata(launcherPath(name))
+		if err != nil || meta == nil {
+			continue
+		}
+		store[name] = meta
+		added = append(added, name)
+	}
+
+	if len(added) > 0 {
+		if err := SaveMetadata(store); err != nil {
+			return nil, err
+		}
+	}
+	return added, nil
+}
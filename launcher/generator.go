@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 )
 
@@ -25,10 +26,21 @@ func DetectLauncherType(target string) LauncherType {
 	return TypeApplication
 }
 
+// GenerateScript renders the launcher script for metadata. The script embeds
+// its own metadata (without secrets), prints its help for the help flag,
+// records each run in the usage log and is stamped with the generator
+// version and a hash of its content.
 func GenerateScript(target string, metadata *LauncherMetadata) string {
+	return stampScript(embedMetadata(withHelp(withUsage(generateScript(target, metadata)), metadata), metadata))
+}
+
+func generateScript(target string, metadata *LauncherMetadata) string {
 	if metadata.Type == TypeStack {
 		return generateStackScript(metadata)
 	}
+	if metadata.Type == TypeActions {
+		return scriptHeader("Launcher with actions") + actionsBody(metadata, `${0##*/}`)
+	}
 
 	switch metadata.Type {
 	case TypeURL:
@@ -37,6 +49,8 @@ func GenerateScript(target string, metadata *LauncherMetadata) string {
 		return generateSSHScript(target, metadata.SSHConfig)
 	case TypeCommand:
 		return generateCommandScript(target, metadata.Env)
+	case TypeDirectory:
+		return generateDirectoryScript(target)
 	default:
 		return generateAppScript(target, metadata.Env)
 	}
@@ -46,35 +60,88 @@ func generateStackScript(metadata *LauncherMetadata) string {
 	var commands []string
 
 	// Handle Env vars globally for the script if any
-	envVars := ""
-	if len(metadata.Env) > 0 {
-		for key, value := range metadata.Env {
-			envVars += fmt.Sprintf("export %s=\"%s\"\n", key, value)
-		}
-	}
+	envVars := envExports(metadata.Env)
 
 	for _, t := range metadata.Targets {
-		type_ := DetectLauncherType(t)
-		var cmd string
-		switch type_ {
-		case TypeURL:
-			cmd = getURLCommand(t)
-		case TypeSSH:
-			// Stack SSH doesn't support complex config yet, just basic connection
-			cmd = fmt.Sprintf("ssh %s", t)
-		case TypeCommand:
-			cmd = t
-		default: // App
-			cmd = getAppCommand(t)
-		}
-		commands = append(commands, cmd)
+		commands = append(commands, targetCommand(t))
+	}
+
+	return scriptHeader("Stack launcher") + envVars + "\n" + strings.Join(commands, "\n") + "\n"
+}
+
+// targetCommand is the shell command for one target of a stack or action,
+// whose type is detected from the target itself
+func targetCommand(t string) string {
+	switch DetectLauncherType(t) {
+	case TypeURL:
+		return getURLCommand(t)
+	case TypeSSH:
+		// Stack SSH doesn't support complex config yet, just basic connection
+		return fmt.Sprintf("ssh %s", t)
+	case TypeCommand:
+		return t
+	default: // App
+		return getAppCommand(t)
 	}
+}
 
-	return fmt.Sprintf(`#!/bin/sh
-# Generated by aka - Stack launcher
-%s
-%s
-`, envVars, strings.Join(commands, "\n"))
+// ActionNames returns a launcher's action names in sorted order
+func ActionNames(metadata *LauncherMetadata) []string {
+	names := make([]string, 0, len(metadata.Actions))
+	for name := range metadata.Actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// actionsBody dispatches on the first argument to one of the launcher's
+// actions and lists them when none is given. self is the shell expression
+// for the launcher's name in messages.
+func actionsBody(metadata *LauncherMetadata, self string) string {
+	names := ActionNames(metadata)
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+
+	var b strings.Builder
+	b.WriteString(envExports(metadata.Env))
+	b.WriteString("aka_actions() {\n")
+	fmt.Fprintf(&b, "\techo \"Usage: %s <action>\"\n\techo\n\techo \"Actions:\"\n", self)
+	b.WriteString("\tcat <<'AKA_ACTIONS'\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-*s  %s\n", width, name, strings.ReplaceAll(metadata.Actions[name], "\n", " "))
+	}
+	b.WriteString("AKA_ACTIONS\n}\n")
+
+	b.WriteString("case \"$1\" in\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s)\n\tshift\n\t%s\n\t;;\n", name, targetCommand(metadata.Actions[name]))
+	}
+	b.WriteString("\"\" | -h | --help | help)\n\taka_actions\n\t;;\n")
+	fmt.Fprintf(&b, "*)\n\techo \"%s: unknown action '$1'\" >&2\n\taka_actions >&2\n\texit 1\n\t;;\nesac\n", self)
+	return b.String()
+}
+
+// envExports renders env as export lines, sorted so the script is stable
+func envExports(env map[string]string) string {
+	keys := make([]string, 0, len(env))
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "export %s=\"%s\"\n", key, env[key])
+	}
+	return b.String()
+}
+
+// scriptHeader returns the shebang and ownership marker every launcher starts with
+func scriptHeader(kind string) string {
+	return fmt.Sprintf("#!/bin/sh\n# Generated by aka - %s\n%s\n", kind, ManagedMarker)
 }
 
 func getURLCommand(url string) string {
@@ -102,10 +169,7 @@ func getAppCommand(appName string) string {
 func generateURLScript(url string) string {
 	cmd := getURLCommand(url)
 
-	return fmt.Sprintf(`#!/bin/sh
-# Generated by aka - URL launcher
-%s
-`, cmd)
+	return scriptHeader("URL launcher") + cmd + "\n"
 }
 
 func generateSSHScript(target string, config *SSHConfig) string {
@@ -132,38 +196,37 @@ func generateSSHScript(target string, config *SSHConfig) string {
 		cmd = fmt.Sprintf(`ssh%s %s`, flagStr, target)
 	}
 
-	return fmt.Sprintf(`#!/bin/sh
-# Generated by aka - SSH launcher
-%s
-`, cmd)
+	return scriptHeader("SSH launcher") + cmd + "\n"
 }
 
 func generateCommandScript(command string, env map[string]string) string {
-	envVars := ""
-	if len(env) > 0 {
-		for key, value := range env {
-			envVars += fmt.Sprintf("export %s=\"%s\"\n", key, value)
-		}
-	}
+	envVars := envExports(env)
 
-	return fmt.Sprintf(`#!/bin/sh
-# Generated by aka - Command launcher
-%s%s
-`, envVars, command)
+	return scriptHeader("Command launcher") + envVars + command + "\n"
 }
 
-func generateAppScript(appName string, env map[string]string) string {
-	envVars := ""
-	if len(env) > 0 {
-		for key, value := range env {
-			envVars += fmt.Sprintf("export %s=\"%s\"\n", key, value)
-		}
+// generateDirectoryScript cannot change the caller's directory, so it opens
+// a new shell there instead. Loaded through 'aka shell-init', the launcher is
+// a function and jumps in place.
+func generateDirectoryScript(dir string) string {
+	return scriptHeader("Directory launcher") + fmt.Sprintf("cd %s || exit 1\nexec \"${SHELL:-/bin/sh}\"\n", shellPath(dir))
+}
+
+// shellPath quotes a path for the shell, keeping a leading ~ expandable
+func shellPath(path string) string {
+	if path == "~" {
+		return `"$HOME"`
 	}
+	if strings.HasPrefix(path, "~/") {
+		return `"$HOME/` + path[2:] + `"`
+	}
+	return `"` + path + `"`
+}
+
+func generateAppScript(appName string, env map[string]string) string {
+	envVars := envExports(env)
 
 	cmd := getAppCommand(appName)
 
-	return fmt.Sprintf(`#!/bin/sh
-# Generated by aka - launcher for %s
-%s%s
-`, appName, envVars, cmd)
+	return scriptHeader("launcher for "+appName) + envVars + cmd + "\n"
 }
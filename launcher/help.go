@@ -0,0 +1,206 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultHelpFlag is the argument generated scripts answer with their help
+// text. It is unusual on purpose so it cannot clash with real arguments.
+const DefaultHelpFlag = "--aka-help"
+
+var helpFlagPattern = regexp.MustCompile(`^--[a-z0-9][a-z0-9-]*$`)
+
+// HelpItem is a labelled line of a launcher's help
+type HelpItem struct {
+	Label string
+	Value string
+}
+
+// LauncherHelp is what a launcher tells about itself, through its help flag
+// or 'aka help <launcher>'
+type LauncherHelp struct {
+	Description string
+	Type        LauncherType
+	// Summary says what running the launcher does
+	Summary string
+	// Usage is what goes after the name on the command line, "" for nothing
+	Usage   string
+	Details []HelpItem
+	Actions []HelpItem
+	// Examples are argument lists to run the launcher with; "" runs it bare
+	Examples []string
+}
+
+func helpFlagPath() string {
+	return filepath.Join(configRoot(), "help-flag")
+}
+
+// HelpFlag returns the argument generated scripts print their help for
+func HelpFlag() string {
+	data, err := os.ReadFile(helpFlagPath())
+	if err != nil {
+		return DefaultHelpFlag
+	}
+	if flag := strings.TrimSpace(string(data)); helpFlagPattern.MatchString(flag) {
+		return flag
+	}
+	return DefaultHelpFlag
+}
+
+// SetHelpFlag changes the help argument for scripts generated from now on
+func SetHelpFlag(flag string) error {
+	if !helpFlagPattern.MatchString(flag) {
+		return fmt.Errorf("invalid flag '%s': use a long option such as %s", flag, DefaultHelpFlag)
+	}
+	if err := os.MkdirAll(configRoot(), 0755); err != nil {
+		return err
+	}
+	if flag == DefaultHelpFlag {
+		if err := os.Remove(helpFlagPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(helpFlagPath(), []byte(flag+"\n"), 0644)
+}
+
+// Help describes a launcher from its definition
+func Help(meta *LauncherMetadata) LauncherHelp {
+	h := LauncherHelp{Description: meta.Description, Type: meta.Type, Examples: []string{""}}
+
+	switch meta.Type {
+	case TypeURL:
+		h.Summary = fmt.Sprintf("Opens %s in the default browser", meta.Target)
+	case TypeSSH:
+		h.Summary = fmt.Sprintf("Connects to %s over SSH", meta.Target)
+		if ssh := meta.SSHConfig; ssh != nil {
+			if ssh.Port != 0 {
+				h.Details = append(h.Details, HelpItem{"Port", fmt.Sprintf("%d", ssh.Port)})
+			}
+			if ssh.KeyFile != "" {
+				h.Details = append(h.Details, HelpItem{"Key file", ssh.KeyFile})
+			}
+		}
+	case TypeCommand:
+		h.Summary = "Runs: " + meta.Target
+	case TypeDirectory:
+		h.Summary = fmt.Sprintf("Opens a shell in %s (jumps there when loaded with 'aka shell-init')", meta.Target)
+	case TypeStack:
+		h.Summary = fmt.Sprintf("Launches %d target(s) at once", len(meta.Targets))
+		for i, t := range meta.Targets {
+			h.Details = append(h.Details, HelpItem{fmt.Sprintf("Target %d", i+1), t})
+		}
+	case TypeActions:
+		h.Summary = fmt.Sprintf("Runs one of %d actions; without one, lists them", len(meta.Actions))
+		h.Usage = "<action>"
+		h.Examples = nil
+		for _, name := range ActionNames(meta) {
+			h.Actions = append(h.Actions, HelpItem{name, meta.Actions[name]})
+			h.Examples = append(h.Examples, name)
+		}
+	default:
+		h.Summary = fmt.Sprintf("Opens the application %s", meta.Target)
+	}
+
+	keys := make([]string, 0, len(meta.Env))
+	for k := range meta.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		h.Details = append(h.Details, HelpItem{"Env " + k, meta.Env[k]})
+	}
+	if len(meta.Aliases) > 0 {
+		h.Details = append(h.Details, HelpItem{"Aliases", strings.Join(meta.Aliases, ", ")})
+	}
+	if len(meta.Tags) > 0 {
+		h.Details = append(h.Details, HelpItem{"Tags", strings.Join(meta.Tags, ", ")})
+	}
+	return h
+}
+
+// helpNameMark stands for the launcher's name in help lines; scripts print
+// the name they were run as, so renamed launchers stay correct
+const helpNameMark = "\x00"
+
+// withHelp adds the help block right after the header comments, before the
+// usage line, so asking for help does not count as a run
+func withHelp(script string, meta *LauncherMetadata) string {
+	h := Help(meta)
+	n := helpNameMark
+
+	lines := []string{n}
+	if h.Description != "" {
+		lines[0] += " - " + h.Description
+	}
+	lines = append(lines, "", "  "+h.Summary, "")
+
+	usage := n
+	if h.Usage != "" {
+		usage += " " + h.Usage
+	}
+	fields := append([]HelpItem{{"Type", string(h.Type)}, {"Usage", usage}}, h.Details...)
+	lines = append(lines, helpColumns(fields, ":")...)
+	if len(h.Actions) > 0 {
+		lines = append(lines, "", "Actions:")
+		lines = append(lines, helpColumns(h.Actions, "")...)
+	}
+	lines = append(lines, "", "Examples:")
+	for _, e := range h.Examples {
+		lines = append(lines, strings.TrimRight("  "+n+" "+e, " "))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "if [ \"$1\" = %s ]; then\n", singleQuote(HelpFlag()))
+	for _, line := range lines {
+		b.WriteString("\tprintf '%s\\n' " + helpLineWords(line) + "\n")
+	}
+	b.WriteString("\texit 0\nfi\n")
+
+	parts := strings.SplitAfter(script, "\n")
+	i := 0
+	for i < len(parts) && strings.HasPrefix(parts[i], "#") {
+		i++
+	}
+	return strings.Join(parts[:i], "") + b.String() + strings.Join(parts[i:], "")
+}
+
+// helpColumns renders items as indented label/value lines with the values
+// lined up; sep follows each label
+func helpColumns(items []HelpItem, sep string) []string {
+	width := 0
+	for _, item := range items {
+		width = max(width, len(item.Label)+len(sep))
+	}
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = fmt.Sprintf("  %-*s  %s", width, item.Label+sep, strings.ReplaceAll(item.Value, "\n", " "))
+	}
+	return lines
+}
+
+// helpLineWords quotes a help line for the shell, printing the name the
+// script was run as wherever the line has helpNameMark
+func helpLineWords(line string) string {
+	pieces := strings.Split(line, helpNameMark)
+	var b strings.Builder
+	for i, p := range pieces {
+		if i > 0 {
+			b.WriteString(`"${0##*/}"`)
+		}
+		if p != "" || len(pieces) == 1 {
+			b.WriteString(singleQuote(p))
+		}
+	}
+	return b.String()
+}
+
+// singleQuote quotes s for the shell
+func singleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
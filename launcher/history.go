@@ -0,0 +1,231 @@
+package launcher
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// historyLimit is how many entries the history log keeps
+const historyLimit = 1000
+
+// HistoryOp is the kind of change a history entry records
+type HistoryOp string
+
+const (
+	OpCreate  HistoryOp = "create"
+	OpUpdate  HistoryOp = "update"
+	OpRemove  HistoryOp = "remove"
+	OpRename  HistoryOp = "rename"
+	OpRestore HistoryOp = "restore"
+)
+
+// ErrNothingToUndo is returned by Undo when every recorded change has been undone
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// HistoryEntry records one change to a launcher with its definition before and after
+type HistoryEntry struct {
+	ID      int               `json:"id"`
+	Time    time.Time         `json:"time"`
+	Op      HistoryOp         `json:"op"`
+	Name    string            `json:"name"`
+	NewName string            `json:"new_name,omitempty"`
+	Before  *LauncherMetadata `json:"before,omitempty"`
+	After   *LauncherMetadata `json:"after,omitempty"`
+	// Reverts is the ID of the entry this change undid, if any
+	Reverts int `json:"reverts,omitempty"`
+}
+
+// Summary describes the change in a few words
+func (e HistoryEntry) Summary() string {
+	var s string
+	switch e.Op {
+	case OpRename:
+		s = fmt.Sprintf("renamed %s -> %s", e.Name, e.NewName)
+	case OpUpdate:
+		s = strings.Join(FieldChanges(e.Before, e.After), "; ")
+		if s == "" {
+			s = "secrets changed"
+		}
+	case OpCreate:
+		s = "created"
+	case OpRemove:
+		s = "removed (in trash)"
+	case OpRestore:
+		s = "restored from trash"
+	}
+	if e.Reverts != 0 {
+		s = fmt.Sprintf("undo #%d: %s", e.Reverts, s)
+	}
+	return s
+}
+
+func getHistoryPath() string {
+	return filepath.Join(GetStateDir(), "history.jsonl")
+}
+
+// LoadHistory returns all recorded changes, oldest first
+func LoadHistory() ([]HistoryEntry, error) {
+	f, err := os.Open(getHistoryPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // a torn line must not make the rest unreadable
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// recordHistory appends an entry and returns its ID. Identical definitions
+// before and after, such as a rebuild, are not recorded and return 0.
+func recordHistory(e HistoryEntry) (int, error) {
+	if e.Op == OpUpdate && SameDefinition(e.Before, e.After) && password(e.Before) == password(e.After) {
+		return 0, nil
+	}
+
+	entries, err := LoadHistory()
+	if err != nil {
+		return 0, err
+	}
+
+	e.ID = 1
+	if len(entries) > 0 {
+		e.ID = entries[len(entries)-1].ID + 1
+	}
+	e.Time = time.Now().UTC().Truncate(time.Second)
+	entries = append(entries, e)
+	if len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return 0, err
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.MkdirAll(GetStateDir(), 0755); err != nil {
+		return 0, err
+	}
+	// Entries may hold saved passwords, like launchers.json
+	return e.ID, os.WriteFile(getHistoryPath(), buf.Bytes(), 0600)
+}
+
+func password(meta *LauncherMetadata) string {
+	if meta == nil || meta.SSHConfig == nil {
+		return ""
+	}
+	return meta.SSHConfig.Password
+}
+
+// LauncherHistory returns the changes that involved name, oldest first
+func LauncherHistory(name string) ([]HistoryEntry, error) {
+	entries, err := LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+	var matched []HistoryEntry
+	for _, e := range entries {
+		if e.Name == name || e.NewName == name {
+			matched = append(matched, e)
+		}
+	}
+	return matched, nil
+}
+
+// LastUndoable returns the most recent change that has not been undone.
+// Undo entries themselves are skipped, so repeated undos walk further back.
+func LastUndoable() (*HistoryEntry, error) {
+	entries, err := LoadHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	undone := make(map[int]bool)
+	for _, e := range entries {
+		if e.Reverts != 0 {
+			undone[e.Reverts] = true
+		}
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if e := entries[i]; e.Reverts == 0 && !undone[e.ID] {
+			return &e, nil
+		}
+	}
+	return nil, ErrNothingToUndo
+}
+
+// Undo reverts the most recent change that has not been undone yet
+func Undo() (*HistoryEntry, error) {
+	e, err := LastUndoable()
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Op {
+	case OpCreate, OpRestore:
+		err = remove(e.Name, e.ID)
+	case OpUpdate:
+		if e.Before == nil {
+			return nil, fmt.Errorf("change #%d has no earlier definition to go back to", e.ID)
+		}
+		err = create(e.Name, e.Before, e.ID)
+	case OpRename:
+		if Exists(e.Name) {
+			return nil, fmt.Errorf("cannot rename back: '%s' exists again", e.Name)
+		}
+		err = rename(e.NewName, e.Name, e.ID)
+	case OpRemove:
+		var item *TrashItem
+		item, err = findTrash(e.ID)
+		if err == nil {
+			err = restore(item, e.Name, e.ID)
+		}
+	default:
+		err = fmt.Errorf("unknown operation '%s'", e.Op)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// RevertTo sets a launcher back to its definition right after history entry id
+func RevertTo(name string, id int) error {
+	entries, err := LauncherHistory(name)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.ID != id {
+			continue
+		}
+		if e.After == nil || (e.Op == OpRename && e.NewName != name) || (e.Op != OpRename && e.Name != name) {
+			return fmt.Errorf("revision #%d does not leave '%s' with a definition", id, name)
+		}
+		return Create(name, e.After)
+	}
+	return fmt.Errorf("'%s' has no revision #%d", name, id)
+}
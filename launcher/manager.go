@@ -5,122 +5,355 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
-func GetLauncherDir() string {
-	if dir := os.Getenv("AKA_BIN_DIR"); dir != "" {
-		return dir
-	}
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return filepath.Join("/tmp", "aka-launchers")
-	}
-	return filepath.Join(home, "bin")
-}
-
 func EnsureLauncherDir() error {
 	dir := GetLauncherDir()
 	return os.MkdirAll(dir, 0755)
 }
 
 func Exists(name string) bool {
-	path := filepath.Join(GetLauncherDir(), name)
+	path := launcherPath(name)
 	_, err := os.Stat(path)
 	return err == nil
 }
 
 func Create(name string, metadata *LauncherMetadata) error {
+	return create(name, metadata, 0)
+}
+
+// create writes a launcher and records the change in the history log.
+// reverts is the ID of the history entry being undone, or 0.
+func create(name string, metadata *LauncherMetadata, reverts int) error {
 	if err := EnsureLauncherDir(); err != nil {
 		return fmt.Errorf("failed to create launcher directory: %w", err)
 	}
 
-	path := filepath.Join(GetLauncherDir(), name)
+	if err := ensureManaged(name); err != nil {
+		return err
+	}
+	if err := ensureNotAlias(name); err != nil {
+		return err
+	}
+	if err := checkAliases(name, metadata); err != nil {
+		return err
+	}
+	_ = os.MkdirAll(GetStateDir(), 0755) // for the usage log
+
+	metadata = withCreatedAt(name, metadata)
+	path := launcherPath(name)
+	before, _ := GetMetadata(name)
 	script := GenerateScript(metadata.Target, metadata)
 
+	backup, err := backupScript(path)
+	if err != nil {
+		return fmt.Errorf("failed to read existing launcher: %w", err)
+	}
+
 	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
-		return fmt.Errorf("failed to write launcher file: %w", err)
+		return rollback(fmt.Errorf("failed to write launcher file: %w", err), backup.restore)
 	}
 
 	if err := SetMetadata(name, metadata); err != nil {
-		return fmt.Errorf("failed to save metadata: %w", err)
+		return rollback(fmt.Errorf("failed to save metadata: %w", err), backup.restore)
 	}
 
+	if err := linkAliases(name, before, metadata); err != nil {
+		return err
+	}
+
+	op := OpCreate
+	if backup.existed {
+		op = OpUpdate
+	}
+	_, _ = recordHistory(HistoryEntry{Op: op, Name: name, Before: before, After: metadata, Reverts: reverts})
 	return nil
 }
 
+// Remove deletes a launcher, keeping a copy in the trash
 func Remove(name string) error {
-	path := filepath.Join(GetLauncherDir(), name)
+	return remove(name, 0)
+}
+
+func remove(name string, reverts int) error {
+	if err := ensureManaged(name); err != nil {
+		return err
+	}
+	if err := ensureNotAlias(name); err != nil {
+		return err
+	}
+
+	path := launcherPath(name)
+	meta, _ := GetMetadata(name)
+
+	backup, err := backupScript(path)
+	if err != nil {
+		return fmt.Errorf("failed to read launcher: %w", err)
+	}
+
 	if err := os.Remove(path); err != nil {
 		return fmt.Errorf("failed to remove launcher: %w", err)
 	}
 
 	if err := DeleteMetadata(name); err != nil {
-		return fmt.Errorf("failed to remove metadata: %w", err)
+		return rollback(fmt.Errorf("failed to remove metadata: %w", err), backup.restore)
 	}
 
+	_ = linkAliases(name, meta, nil)
+	_ = renameUsage(name, "")
+
+	// The trash item shares the history entry's ID so undo can find it
+	id, err := recordHistory(HistoryEntry{Op: OpRemove, Name: name, Before: meta, Reverts: reverts})
+	if err == nil {
+		_ = writeTrash(TrashItem{
+			ID:        id,
+			Name:      name,
+			RemovedAt: time.Now().UTC().Truncate(time.Second),
+			Metadata:  meta,
+			Script:    string(backup.data),
+		})
+	}
 	return nil
 }
 
 func Rename(oldName, newName string) error {
-	oldPath := filepath.Join(GetLauncherDir(), oldName)
-	newPath := filepath.Join(GetLauncherDir(), newName)
+	return rename(oldName, newName, 0)
+}
+
+func rename(oldName, newName string, reverts int) error {
+	oldPath := launcherPath(oldName)
+	newPath := launcherPath(newName)
+
+	if err := ensureManaged(oldName); err != nil {
+		return err
+	}
+	if err := ensureNotAlias(oldName); err != nil {
+		return err
+	}
+
+	store, err := LoadMetadata()
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
 
 	if err := os.Rename(oldPath, newPath); err != nil {
 		return fmt.Errorf("failed to rename launcher: %w", err)
 	}
 
-	store, err := LoadMetadata()
-	if err == nil {
-		if meta, ok := store[oldName]; ok {
-			delete(store, oldName)
-			store[newName] = meta
-			_ = SaveMetadata(store)
+	undo := func() error { return os.Rename(newPath, oldPath) }
+
+	meta, ok := store[oldName]
+	if !ok {
+		// Scripts carry their own metadata, so a missing index entry can be restored
+		meta, _ = ReadScriptMetadata(newPath)
+	}
+	if meta != nil {
+		delete(store, oldName)
+		store[newName] = meta
+		if err := SaveMetadata(store); err != nil {
+			return rollback(fmt.Errorf("failed to save metadata: %w", err), undo)
 		}
 	}
 
+	if meta != nil {
+		// Point the aliases at the new name
+		for _, alias := range meta.Aliases {
+			unlinkAlias(alias)
+		}
+		if err := linkAliases(newName, nil, meta); err != nil {
+			return err
+		}
+	}
+
+	_ = renameUsage(oldName, newName)
+	_, _ = recordHistory(HistoryEntry{Op: OpRename, Name: oldName, NewName: newName, Before: meta, After: meta, Reverts: reverts})
 	return nil
 }
 
+// Update applies edit to a copy of a launcher's definition, validates the
+// result and regenerates the script
+func Update(name string, edit func(meta *LauncherMetadata) error) error {
+	store, err := LoadMetadata()
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+	current, ok := store[name]
+	if !ok || current == nil {
+		return fmt.Errorf("launcher '%s' has no recorded definition", name)
+	}
+
+	meta := *current
+	meta.Targets = append([]string(nil), current.Targets...)
+	meta.Tags = append([]string(nil), current.Tags...)
+	meta.Aliases = append([]string(nil), current.Aliases...)
+	meta.Env = copyMap(current.Env)
+	meta.Actions = copyMap(current.Actions)
+	if current.SSHConfig != nil {
+		ssh := *current.SSHConfig
+		meta.SSHConfig = &ssh
+	}
+
+	if err := edit(&meta); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	Normalize(&meta)
+	if err := Validate(name, &meta); err != nil {
+		return err
+	}
+	return Create(name, &meta)
+}
+
+// copyMap returns a copy of m, or nil if m is nil
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(m))
+	for k, v := range m {
+		copied[k] = v
+	}
+	return copied
+}
+
+// withCreatedAt stamps a new definition with its creation time, keeping the
+// original time when an existing launcher is redefined
+func withCreatedAt(name string, metadata *LauncherMetadata) *LauncherMetadata {
+	if !metadata.CreatedAt.IsZero() {
+		return metadata
+	}
+	stamped := *metadata
+	stamped.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	if store, err := LoadMetadata(); err == nil {
+		if existing := store[name]; existing != nil && !existing.CreatedAt.IsZero() {
+			stamped.CreatedAt = existing.CreatedAt
+		}
+	}
+	return &stamped
+}
+
+// scriptBackup holds the previous state of a launcher script so a failed
+// operation can put it back
+type scriptBackup struct {
+	path    string
+	existed bool
+	data    []byte
+	mode    os.FileMode
+}
+
+func backupScript(path string) (*scriptBackup, error) {
+	backup := &scriptBackup{path: path}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return backup, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	backup.existed = true
+	backup.data = data
+	backup.mode = info.Mode().Perm()
+	return backup, nil
+}
+
+// restore puts the script back the way it was, removing it if it did not exist
+func (b *scriptBackup) restore() error {
+	if !b.existed {
+		if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.WriteFile(b.path, b.data, b.mode); err != nil {
+		return err
+	}
+	return os.Chmod(b.path, b.mode)
+}
+
+// rollback runs undo after a failed step and reports both errors if undo fails too
+func rollback(err error, undo func() error) error {
+	if undoErr := undo(); undoErr != nil {
+		return fmt.Errorf("%w (rollback failed: %v)", err, undoErr)
+	}
+	return err
+}
+
 type LauncherInfo struct {
 	Name   string
 	Target string
+	// Metadata comes from launchers.json or, failing that, from the script
+	// itself; it is nil for scripts that only yield a parsed target
+	Metadata *LauncherMetadata
 }
 
+// List returns the launchers aka manages; other files in the directory are skipped
 func List() ([]LauncherInfo, error) {
-	dir := GetLauncherDir()
-	entries, err := os.ReadDir(dir)
+	entries, err := readLauncherDir()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []LauncherInfo{}, nil
-		}
-		return nil, fmt.Errorf("failed to read launcher directory: %w", err)
+		return nil, err
 	}
 
 	metadata, _ := LoadMetadata()
+	dir := GetLauncherDir()
 
 	var launchers []LauncherInfo
-	for _, entry := range entries {
-		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
-			continue
+	for _, name := range entries {
+		path := filepath.Join(dir, name)
+
+		meta, ok := metadata[name]
+		if !ok || meta == nil {
+			if !hasMarker(path) {
+				continue
+			}
+			meta, _ = ReadScriptMetadata(path)
 		}
 
-		target := "unknown"
-		if meta, ok := metadata[entry.Name()]; ok && meta != nil {
-			target = meta.Target
-		} else {
-			target, _ = extractTarget(filepath.Join(dir, entry.Name()))
+		info := LauncherInfo{Name: name, Metadata: meta}
+		if meta != nil {
+			info.Target = meta.Target
+		} else if info.Target, err = extractTarget(path); err != nil {
+			info.Target = "unknown"
 		}
 
-		launchers = append(launchers, LauncherInfo{
-			Name:   entry.Name(),
-			Target: target,
-		})
+		launchers = append(launchers, info)
 	}
 
 	return launchers, nil
 }
 
-// extractTarget parses a launcher script to extract the target (legacy fallback for launchers without metadata)
+// readLauncherDir returns the names of all non-hidden files in the launcher
+// directory, leaving out alias links
+func readLauncherDir() ([]string, error) {
+	entries, err := os.ReadDir(GetLauncherDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, fmt.Errorf("failed to read launcher directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if _, ok := isAliasLink(entry.Name()); ok {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// extractTarget parses a launcher script to extract the target. It is the last
+// resort for scripts that have neither an index entry nor embedded metadata.
 func extractTarget(path string) (string, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
@@ -7,23 +7,17 @@ import (
 	"path/filepath"
 )
 
-const metadataFile = ".config/aka/launchers.json"
-
 type MetadataStore map[string]*LauncherMetadata
 
-func getMetadataPath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, metadataFile), nil
+// metadataDocument is the on-disk layout of launchers.json
+type metadataDocument struct {
+	Version   int           `json:"version"`
+	Launchers MetadataStore `json:"launchers"`
 }
 
+// LoadMetadata reads the metadata store, migrating older schema versions in place
 func LoadMetadata() (MetadataStore, error) {
-	path, err := getMetadataPath()
-	if err != nil {
-		return nil, err
-	}
+	path := getMetadataPath()
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return make(MetadataStore), nil
@@ -34,26 +28,51 @@ func LoadMetadata() (MetadataStore, error) {
 		return nil, fmt.Errorf("failed to read metadata: %w", err)
 	}
 
-	var store MetadataStore
-	if err := json.Unmarshal(data, &store); err != nil {
+	doc, version, err := decodeDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("metadata schema version %d is newer than this version of aka supports (%d)", version, SchemaVersion)
+	}
+
+	if version < SchemaVersion {
+		if _, err := migrateDocument(doc, version); err != nil {
+			return nil, fmt.Errorf("failed to migrate metadata: %w", err)
+		}
+		if err := os.WriteFile(backupPath(path, version), data, 0600); err != nil {
+			return nil, fmt.Errorf("failed to back up metadata: %w", err)
+		}
+	}
+
+	store, err := storeFromDocument(doc)
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse metadata: %w", err)
 	}
 
+	if version < SchemaVersion {
+		if err := SaveMetadata(store); err != nil {
+			return nil, err
+		}
+	}
+
 	return store, nil
 }
 
 func SaveMetadata(store MetadataStore) error {
-	path, err := getMetadataPath()
-	if err != nil {
-		return err
-	}
+	path := getMetadataPath()
 
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := json.MarshalIndent(store, "", "  ")
+	if store == nil {
+		store = make(MetadataStore)
+	}
+
+	data, err := json.MarshalIndent(metadataDocument{Version: SchemaVersion, Launchers: store}, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
@@ -0,0 +1,221 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// SchemaVersion is the metadata schema version written by this build
+const SchemaVersion = 2
+
+// migration upgrades a metadata document from one schema version to the next
+type migration struct {
+	From        int
+	Description string
+	Apply       func(launchers map[string]map[string]any) []string
+}
+
+// migrations is the ordered upgrade chain; append new steps, never edit old ones
+var migrations = []migration{
+	{
+		From:        0,
+		Description: "Add schema version to launchers.json",
+		Apply: func(launchers map[string]map[string]any) []string {
+			return nil
+		},
+	},
+	{
+		From:        1,
+		Description: "Use 'target' for single launchers and 'targets' for stacks only",
+		Apply:       normalizeTargets,
+	},
+}
+
+// MigrationStep describes one applied (or planned) migration
+type MigrationStep struct {
+	From        int
+	To          int
+	Description string
+	Changes     []string
+}
+
+// MigrationPlan describes what LoadMetadata would do to the current metadata file
+type MigrationPlan struct {
+	Path       string
+	BackupPath string
+	From       int
+	To         int
+	Steps      []MigrationStep
+}
+
+// NeedsMigration reports whether the plan would change anything
+func (p *MigrationPlan) NeedsMigration() bool {
+	return p.From < p.To
+}
+
+// PlanMigration inspects the metadata file without modifying it
+func PlanMigration() (*MigrationPlan, error) {
+	path := getMetadataPath()
+	plan := &MigrationPlan{Path: path, From: SchemaVersion, To: SchemaVersion}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return plan, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata: %w", err)
+	}
+
+	doc, version, err := decodeDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metadata: %w", err)
+	}
+
+	plan.From = version
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("metadata schema version %d is newer than this version of aka supports (%d)", version, SchemaVersion)
+	}
+
+	if version < SchemaVersion {
+		plan.BackupPath = backupPath(path, version)
+		steps, err := migrateDocument(doc, version)
+		if err != nil {
+			return nil, err
+		}
+		plan.Steps = steps
+	}
+
+	return plan, nil
+}
+
+// decodeDocument parses launchers.json into its generic form and reports its
+// schema version. Files written before versioning are a bare name->launcher map.
+func decodeDocument(data []byte) (map[string]any, int, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, err
+	}
+	if raw == nil {
+		raw = make(map[string]any)
+	}
+
+	if v, ok := raw["version"].(float64); ok {
+		if _, ok := raw["launchers"].(map[string]any); !ok {
+			raw["launchers"] = make(map[string]any)
+		}
+		return raw, int(v), nil
+	}
+
+	return map[string]any{"version": float64(0), "launchers": raw}, 0, nil
+}
+
+// migrateDocument runs every migration from version up to SchemaVersion
+func migrateDocument(doc map[string]any, version int) ([]MigrationStep, error) {
+	launchers := make(map[string]map[string]any)
+	for name, entry := range doc["launchers"].(map[string]any) {
+		if entry == nil {
+			continue
+		}
+		m, ok := entry.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("launcher '%s' is not an object", name)
+		}
+		launchers[name] = m
+	}
+
+	var steps []MigrationStep
+	for _, m := range migrations {
+		if m.From < version {
+			continue
+		}
+		changes := m.Apply(launchers)
+		sort.Strings(changes)
+		steps = append(steps, MigrationStep{
+			From:        m.From,
+			To:          m.From + 1,
+			Description: m.Description,
+			Changes:     changes,
+		})
+		doc["version"] = float64(m.From + 1)
+	}
+
+	migrated := make(map[string]any, len(launchers))
+	for name, m := range launchers {
+		migrated[name] = m
+	}
+	doc["launchers"] = migrated
+
+	return steps, nil
+}
+
+// storeFromDocument converts a generic document into the typed store
+func storeFromDocument(doc map[string]any) (MetadataStore, error) {
+	data, err := json.Marshal(doc["launchers"])
+	if err != nil {
+		return nil, err
+	}
+
+	store := make(MetadataStore)
+	if err := json.Unmarshal(data, &store); err != nil {
+		return nil, err
+	}
+	for name, meta := range store {
+		if meta == nil {
+			delete(store, name)
+		}
+	}
+	return store, nil
+}
+
+func backupPath(path string, version int) string {
+	return fmt.Sprintf("%s.v%d.bak", path, version)
+}
+
+// normalizeTargets removes the duplicated target/targets fields written by
+// early versions, which stored both for every launcher
+func normalizeTargets(launchers map[string]map[string]any) []string {
+	var changes []string
+
+	for name, m := range launchers {
+		target, _ := m["target"].(string)
+		targets, _ := m["targets"].([]any)
+		typ, _ := m["type"].(string)
+
+		if typ == "" {
+			if len(targets) > 1 {
+				typ = string(TypeStack)
+			} else {
+				typ = string(DetectLauncherType(target))
+			}
+			m["type"] = typ
+			changes = append(changes, fmt.Sprintf("%s: set missing type to '%s'", name, typ))
+		}
+
+		if typ == string(TypeStack) {
+			if len(targets) == 0 && target != "" {
+				m["targets"] = []any{target}
+				changes = append(changes, fmt.Sprintf("%s: move target into targets", name))
+			}
+			if _, ok := m["target"]; ok {
+				delete(m, "target")
+				changes = append(changes, fmt.Sprintf("%s: drop legacy target field", name))
+			}
+			continue
+		}
+
+		if target == "" && len(targets) > 0 {
+			if first, ok := targets[0].(string); ok {
+				m["target"] = first
+				changes = append(changes, fmt.Sprintf("%s: move targets[0] into target", name))
+			}
+		}
+		if _, ok := m["targets"]; ok {
+			delete(m, "targets")
+			changes = append(changes, fmt.Sprintf("%s: drop redundant targets field", name))
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,151 @@
+package launcher
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ManagedMarker is written near the top of every generated script so aka can
+// tell its own files apart from anything else living in the bin directory
+const ManagedMarker = "# aka:managed"
+
+// legacyMarker identifies scripts generated before the managed marker existed
+const legacyMarker = "# Generated by aka"
+
+// markerScanLines is how far into a file the marker is searched for
+const markerScanLines = 5
+
+// ErrNotManaged is returned when an operation targets a file aka did not create
+var ErrNotManaged = errors.New("not managed by aka")
+
+// IsManaged reports whether aka owns the named file, either because the
+// metadata index knows about it or because the script carries the marker
+func IsManaged(name string) bool {
+	if meta, err := GetMetadata(name); err == nil && meta != nil {
+		return true
+	}
+	return hasMarker(launcherPath(name))
+}
+
+// ensureManaged returns ErrNotManaged if an existing file at name is not owned by aka
+func ensureManaged(name string) error {
+	if Exists(name) && !IsManaged(name) {
+		return fmt.Errorf("'%s' is %w (use --adopt to take it over)", name, ErrNotManaged)
+	}
+	return nil
+}
+
+func hasMarker(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < markerScanLines && scanner.Scan(); i++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, ManagedMarker) || strings.HasPrefix(line, legacyMarker) {
+			return true
+		}
+	}
+	return false
+}
+
+// scriptType reads the launcher type from a generated script's header line
+func scriptType(path string) (LauncherType, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	kinds := map[string]LauncherType{
+		"URL launcher":     TypeURL,
+		"SSH launcher":     TypeSSH,
+		"Command launcher": TypeCommand,
+		"Stack launcher":   TypeStack,
+	}
+
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < markerScanLines && scanner.Scan(); i++ {
+		kind, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), legacyMarker+" - ")
+		if !ok {
+			continue
+		}
+		if t, ok := kinds[kind]; ok {
+			return t, true
+		}
+		if strings.HasPrefix(kind, "launcher for ") {
+			return TypeApplication, true
+		}
+	}
+	return "", false
+}
+
+// Adopt records a file in the metadata index so aka treats it as its own,
+// using the script's embedded metadata when it has any. The file itself is
+// left untouched.
+func Adopt(name string) error {
+	if meta, err := GetMetadata(name); err != nil {
+		return err
+	} else if meta != nil {
+		return nil
+	}
+
+	path := launcherPath(name)
+	if meta, err := ReadScriptMetadata(path); err == nil && meta != nil {
+		if err := SetMetadata(name, meta); err != nil {
+			return fmt.Errorf("failed to save metadata: %w", err)
+		}
+		return nil
+	}
+
+	target, err := extractTarget(path)
+	if err != nil {
+		return fmt.Errorf("failed to read '%s': %w", name, err)
+	}
+
+	launcherType, ok := scriptType(path)
+	if !ok {
+		launcherType = DetectLauncherType(target)
+	}
+
+	metadata := &LauncherMetadata{
+		Type:   launcherType,
+		Target: target,
+	}
+	if err := SetMetadata(name, metadata); err != nil {
+		return fmt.Errorf("failed to save metadata: %w", err)
+	}
+	return nil
+}
+
+// ListForeign returns files in the launcher directory that aka does not manage
+func ListForeign() ([]LauncherInfo, error) {
+	entries, err := readLauncherDir()
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := LoadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := GetLauncherDir()
+	var foreign []LauncherInfo
+	for _, name := range entries {
+		if _, ok := metadata[name]; ok || hasMarker(filepath.Join(dir, name)) {
+			continue
+		}
+		target, _ := extractTarget(filepath.Join(dir, name))
+		foreign = append(foreign, LauncherInfo{Name: name, Target: target})
+	}
+
+	return foreign, nil
+}
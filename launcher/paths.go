@@ -0,0 +1,116 @@
+package launcher
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Directory overrides set from command-line flags; they take precedence over
+// every environment variable
+var (
+	binDirOverride    string
+	configDirOverride string
+)
+
+// SetBinDir overrides the launcher directory for this process
+func SetBinDir(dir string) {
+	binDirOverride = dir
+}
+
+// SetConfigDir overrides the configuration directory for this process
+func SetConfigDir(dir string) {
+	configDirOverride = dir
+}
+
+// GetLauncherDir returns the directory launchers are written to.
+// Order: --bin-dir, the active profile's bin directory, AKA_BIN_DIR, $AKA_HOME/bin, ~/bin.
+func GetLauncherDir() string {
+	if binDirOverride != "" {
+		return binDirOverride
+	}
+	if profile := ActiveProfile(); profile != DefaultProfile {
+		return filepath.Join(profileDir(profile), "bin")
+	}
+	if dir := os.Getenv("AKA_BIN_DIR"); dir != "" {
+		return dir
+	}
+	if home := os.Getenv("AKA_HOME"); home != "" {
+		return filepath.Join(home, "bin")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join("/tmp", "aka-launchers")
+	}
+	return filepath.Join(home, "bin")
+}
+
+// GetConfigDir returns the directory holding the active profile's launchers.json
+func GetConfigDir() string {
+	if profile := ActiveProfile(); profile != DefaultProfile {
+		return profileDir(profile)
+	}
+	return configRoot()
+}
+
+// configRoot returns the top-level configuration directory shared by all profiles.
+// Order: --config-dir, AKA_CONFIG_DIR, $AKA_HOME/config, $XDG_CONFIG_HOME/aka, ~/.config/aka.
+func configRoot() string {
+	if configDirOverride != "" {
+		return configDirOverride
+	}
+	if dir := os.Getenv("AKA_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	if home := os.Getenv("AKA_HOME"); home != "" {
+		return filepath.Join(home, "config")
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "aka")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join("/tmp", "aka-config")
+	}
+	return filepath.Join(home, ".config", "aka")
+}
+
+// GetStateDir returns the active profile's directory for runtime state such as logs and caches
+func GetStateDir() string {
+	if profile := ActiveProfile(); profile != DefaultProfile {
+		return filepath.Join(stateRoot(), "profiles", profile)
+	}
+	return stateRoot()
+}
+
+// stateRoot returns the top-level state directory shared by all profiles.
+// Order: AKA_STATE_DIR, $AKA_HOME/state, $XDG_STATE_HOME/aka, ~/.local/state/aka.
+func stateRoot() string {
+	if dir := os.Getenv("AKA_STATE_DIR"); dir != "" {
+		return dir
+	}
+	if home := os.Getenv("AKA_HOME"); home != "" {
+		return filepath.Join(home, "state")
+	}
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "aka")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join("/tmp", "aka-state")
+	}
+	return filepath.Join(home, ".local", "state", "aka")
+}
+
+// launcherPath returns the script path for a launcher name
+func launcherPath(name string) string {
+	return filepath.Join(GetLauncherDir(), name)
+}
+
+// ScriptPath returns where the script for a launcher name lives
+func ScriptPath(name string) string {
+	return launcherPath(name)
+}
+
+func getMetadataPath() string {
+	return filepath.Join(GetConfigDir(), "launchers.json")
+}
@@ -0,0 +1,115 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+)
+
+// FileOp is what an operation would do to a file
+type FileOp string
+
+const (
+	FileCreate FileOp = "create"
+	FileUpdate FileOp = "update"
+	FileDelete FileOp = "delete"
+	FileRename FileOp = "rename"
+)
+
+// FileChange is a file an operation would touch. Diff is a unified diff of
+// the script; it is empty for the metadata index, which may hold secrets.
+type FileChange struct {
+	Op      FileOp
+	Path    string
+	NewPath string
+	Diff    string
+}
+
+// PreviewCreate returns the files Create would write for name and metadata,
+// without changing anything
+func PreviewCreate(name string, metadata *LauncherMetadata) ([]FileChange, error) {
+	path := launcherPath(name)
+	current, err := readScript(path)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata = withCreatedAt(name, metadata)
+	next := GenerateScript(metadata.Target, metadata)
+
+	op := FileCreate
+	if current != nil {
+		op = FileUpdate
+	}
+	var changes []FileChange
+	if current == nil || *current != next {
+		changes = append(changes, FileChange{
+			Op:   op,
+			Path: path,
+			Diff: UnifiedDiff(deref(current), next, name+" (current)", name+" (new)"),
+		})
+	}
+
+	existing, err := GetMetadata(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+	if !SameDefinition(existing, metadata) || password(existing) != password(metadata) {
+		changes = append(changes, FileChange{Op: FileUpdate, Path: getMetadataPath()})
+	}
+	return changes, nil
+}
+
+// PreviewRemove returns the files Remove would delete or change for name
+func PreviewRemove(name string) ([]FileChange, error) {
+	path := launcherPath(name)
+	current, err := readScript(path)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("launcher '%s' does not exist", name)
+	}
+
+	changes := []FileChange{{
+		Op:   FileDelete,
+		Path: path,
+		Diff: UnifiedDiff(*current, "", name, "/dev/null"),
+	}}
+	if meta, _ := GetMetadata(name); meta != nil {
+		changes = append(changes, FileChange{Op: FileUpdate, Path: getMetadataPath()})
+	}
+	return changes, nil
+}
+
+// PreviewRename returns the files Rename would move or change
+func PreviewRename(oldName, newName string) ([]FileChange, error) {
+	if !Exists(oldName) {
+		return nil, fmt.Errorf("launcher '%s' does not exist", oldName)
+	}
+
+	changes := []FileChange{{Op: FileRename, Path: launcherPath(oldName), NewPath: launcherPath(newName)}}
+	if meta, _ := GetMetadata(oldName); meta != nil {
+		changes = append(changes, FileChange{Op: FileUpdate, Path: getMetadataPath()})
+	}
+	return changes, nil
+}
+
+// readScript returns the content of a launcher script, or nil if there is none
+func readScript(path string) (*string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read launcher: %w", err)
+	}
+	content := string(data)
+	return &content, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,176 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile is the profile used when none has been selected
+const DefaultProfile = "default"
+
+// profileOverride is set from the --profile flag
+var profileOverride string
+
+// SetProfile selects the profile for this process without changing the saved default
+func SetProfile(name string) {
+	profileOverride = name
+}
+
+// ActiveProfile returns the profile in use.
+// Order: --profile, AKA_PROFILE, the profile saved by 'aka profile use', default.
+func ActiveProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	if name := os.Getenv("AKA_PROFILE"); name != "" {
+		return name
+	}
+	data, err := os.ReadFile(activeProfilePath())
+	if err == nil {
+		if name := strings.TrimSpace(string(data)); name != "" {
+			return name
+		}
+	}
+	return DefaultProfile
+}
+
+func activeProfilePath() string {
+	return filepath.Join(configRoot(), "profile")
+}
+
+func profileDir(name string) string {
+	return filepath.Join(configRoot(), "profiles", name)
+}
+
+// ProfileExists reports whether a profile has been created
+func ProfileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	info, err := os.Stat(profileDir(name))
+	return err == nil && info.IsDir()
+}
+
+// ListProfiles returns every profile name, default first
+func ListProfiles() ([]string, error) {
+	profiles := []string{DefaultProfile}
+
+	entries, err := os.ReadDir(filepath.Join(configRoot(), "profiles"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return profiles, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles: %w", err)
+	}
+
+	var named []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultProfile {
+			named = append(named, entry.Name())
+		}
+	}
+	sort.Strings(named)
+
+	return append(profiles, named...), nil
+}
+
+// CreateProfile sets up the bin directory for a new profile
+func CreateProfile(name string) error {
+	if ProfileExists(name) {
+		return fmt.Errorf("profile '%s' already exists", name)
+	}
+	if err := os.MkdirAll(filepath.Join(profileDir(name), "bin"), 0755); err != nil {
+		return fmt.Errorf("failed to create profile: %w", err)
+	}
+	return nil
+}
+
+// UseProfile saves name as the default profile for future invocations
+func UseProfile(name string) error {
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' does not exist", name)
+	}
+	if err := os.MkdirAll(configRoot(), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if name == DefaultProfile {
+		if err := os.Remove(activeProfilePath()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to save active profile: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(activeProfilePath(), []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save active profile: %w", err)
+	}
+	return nil
+}
+
+// RemoveProfile deletes a profile together with its launchers and metadata
+func RemoveProfile(name string) error {
+	if name == DefaultProfile {
+		return fmt.Errorf("the default profile cannot be removed")
+	}
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile '%s' does not exist", name)
+	}
+	if name == ActiveProfile() {
+		return fmt.Errorf("profile '%s' is active; switch to another profile first", name)
+	}
+	if err := os.RemoveAll(profileDir(name)); err != nil {
+		return fmt.Errorf("failed to remove profile: %w", err)
+	}
+	if err := os.RemoveAll(filepath.Join(stateRoot(), "profiles", name)); err != nil {
+		return fmt.Errorf("failed to remove profile state: %w", err)
+	}
+	return nil
+}
+
+// InProfile runs fn with the given profile active, restoring the previous one
+// afterwards. A --bin-dir override is suspended so fn sees the profile's own directory.
+func InProfile(name string, fn func() error) error {
+	previousProfile, previousBinDir := profileOverride, binDirOverride
+	profileOverride, binDirOverride = name, ""
+	defer func() { profileOverride, binDirOverride = previousProfile, previousBinDir }()
+	return fn()
+}
+
+// MoveToProfile moves a launcher from the active profile into another one
+func MoveToProfile(name, profile string) error {
+	from := ActiveProfile()
+	if profile == from {
+		return fmt.Errorf("launcher '%s' is already in profile '%s'", name, profile)
+	}
+	if !ProfileExists(profile) {
+		return fmt.Errorf("profile '%s' does not exist", profile)
+	}
+
+	metadata, err := GetMetadata(name)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+	if metadata == nil {
+		return fmt.Errorf("launcher '%s' has no metadata and cannot be moved", name)
+	}
+
+	err = InProfile(profile, func() error {
+		if Exists(name) {
+			return fmt.Errorf("launcher '%s' already exists in profile '%s'", name, profile)
+		}
+		return Create(name, metadata)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := Remove(name); err != nil {
+		undo := func() error {
+			return InProfile(profile, func() error { return Remove(name) })
+		}
+		return rollback(err, undo)
+	}
+
+	return nil
+}
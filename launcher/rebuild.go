@@ -0,0 +1,142 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RebuildItem is a launcher whose script differs from what the generator produces now
+type RebuildItem struct {
+	Name     string
+	Metadata *LauncherMetadata
+	Current  string
+	Next     string
+	Stamp    ScriptStamp
+	// Edited is set when the script was changed by hand (or never generated
+	// by aka) and rebuilding would discard those changes
+	Edited bool
+}
+
+// Reason summarizes why the launcher needs rebuilding
+func (r RebuildItem) Reason() string {
+	switch {
+	case r.Edited && r.Stamp.Generator == 0:
+		return "not generated by aka"
+	case r.Edited:
+		return "edited by hand"
+	case r.Current == "":
+		return "script missing"
+	case r.Stamp.Generator < GeneratorVersion:
+		return fmt.Sprintf("generator v%d -> v%d", r.Stamp.Generator, GeneratorVersion)
+	default:
+		return "metadata changed"
+	}
+}
+
+// Diff returns a unified diff from the current script to the rebuilt one
+func (r RebuildItem) Diff() string {
+	return UnifiedDiff(r.Current, r.Next, r.Name+" (current)", r.Name+" (rebuilt)")
+}
+
+// Apply writes the rebuilt script
+func (r RebuildItem) Apply() error {
+	return Create(r.Name, r.Metadata)
+}
+
+// PlanRebuild compares the named launchers (or all launchers when names is
+// empty) with freshly generated scripts and returns those that differ
+func PlanRebuild(names []string) ([]RebuildItem, error) {
+	store, err := LoadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		for name := range store {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var items []RebuildItem
+	for _, name := range names {
+		meta, ok := store[name]
+		if !ok || meta == nil {
+			return nil, fmt.Errorf("launcher '%s' has no metadata", name)
+		}
+
+		path := launcherPath(name)
+		current, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read launcher '%s': %w", name, err)
+		}
+
+		next := GenerateScript(meta.Target, meta)
+		if string(current) == next {
+			continue
+		}
+
+		stamp, _ := ReadStamp(string(current))
+		item := RebuildItem{
+			Name:     name,
+			Metadata: meta,
+			Current:  string(current),
+			Next:     next,
+			Stamp:    stamp,
+		}
+		if len(current) > 0 {
+			item.Edited = !hasMarker(path) || IsHandEdited(item.Current)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func generatorStatePath() string {
+	return filepath.Join(GetStateDir(), "generator")
+}
+
+// LastGeneratorVersion returns the generator version recorded by the last
+// automatic rebuild, or 0 if none has run
+func LastGeneratorVersion() int {
+	data, err := os.ReadFile(generatorStatePath())
+	if err != nil {
+		return 0
+	}
+	version, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return version
+}
+
+// RebuildOutdated regenerates every launcher whose script is out of date,
+// skipping hand-edited ones, and records the current generator version
+func RebuildOutdated() ([]string, error) {
+	items, err := PlanRebuild(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var rebuilt []string
+	for _, item := range items {
+		if item.Edited || item.Current == "" {
+			continue
+		}
+		if err := item.Apply(); err != nil {
+			return rebuilt, fmt.Errorf("failed to rebuild '%s': %w", item.Name, err)
+		}
+		rebuilt = append(rebuilt, item.Name)
+	}
+
+	if err := os.MkdirAll(GetStateDir(), 0755); err != nil {
+		return rebuilt, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(generatorStatePath(), []byte(strconv.Itoa(GeneratorVersion)+"\n"), 0644); err != nil {
+		return rebuilt, fmt.Errorf("failed to record generator version: %w", err)
+	}
+
+	return rebuilt, nil
+}
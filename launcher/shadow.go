@@ -0,0 +1,113 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// shellKeywords are reserved words a shell parses before looking up commands
+var shellKeywords = map[string]bool{
+	"if": true, "then": true, "else": true, "elif": true, "fi": true,
+	"case": true, "esac": true, "for": true, "while": true, "until": true,
+	"do": true, "done": true, "in": true, "function": true, "select": true,
+	"time": true, "coproc": true,
+}
+
+// otherBuiltins are the remaining bash and zsh builtins; shellBuiltins only
+// lists the ones that matter when resolving a command's binary
+var otherBuiltins = map[string]bool{
+	"bg": true, "fg": true, "jobs": true, "builtin": true, "command": true,
+	"hash": true, "history": true, "let": true, "local": true, "return": true,
+	"break": true, "continue": true, "declare": true, "typeset": true,
+	"readonly": true, "getopts": true, "times": true, "disown": true,
+	"enable": true, "help": true, "logout": true, "shopt": true, "bind": true,
+	"dirs": true, "fc": true, "complete": true, "compgen": true, "caller": true,
+}
+
+// Shadow describes other commands that share a launcher's name
+type Shadow struct {
+	Name string
+	// Builtin is set for shell builtins and keywords, which always win over
+	// anything on PATH
+	Builtin bool
+	// Paths are the other executables with this name, in PATH order
+	Paths []string
+	// LauncherFirst is set when the launcher directory comes before every
+	// other copy on PATH, so typing the name runs the launcher
+	LauncherFirst bool
+}
+
+// Winner describes which command runs when the name is typed
+func (s *Shadow) Winner() string {
+	switch {
+	case s.Builtin:
+		return fmt.Sprintf("the shell builtin '%s' runs instead of the launcher", s.Name)
+	case s.LauncherFirst:
+		return fmt.Sprintf("the launcher runs instead of %s", s.Paths[0])
+	default:
+		return fmt.Sprintf("%s runs instead of the launcher", s.Paths[0])
+	}
+}
+
+// Detail summarizes the clash in one line
+func (s *Shadow) Detail() string {
+	what := "a shell builtin"
+	if !s.Builtin {
+		what = strings.Join(s.Paths, ", ")
+	} else if len(s.Paths) > 0 {
+		what += " and " + strings.Join(s.Paths, ", ")
+	}
+	return fmt.Sprintf("same name as %s: %s", what, s.Winner())
+}
+
+// FindShadow looks for shell builtins and executables on PATH named like a
+// launcher, ignoring the launcher directory itself. It returns nil if there are none.
+func FindShadow(name string) *Shadow {
+	s := &Shadow{
+		Name:    name,
+		Builtin: shellBuiltins[name] || otherBuiltins[name] || shellKeywords[name],
+	}
+
+	launcherDir := filepath.Clean(GetLauncherDir())
+	launcherSeen := false
+	seen := make(map[string]bool)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		dir = filepath.Clean(dir)
+		if dir == launcherDir {
+			launcherSeen = true
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if !isExecutable(path) {
+			continue
+		}
+		// Directories such as /bin are often links to /usr/bin
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			real = path
+		}
+		if seen[real] {
+			continue
+		}
+		seen[real] = true
+		if len(s.Paths) == 0 {
+			s.LauncherFirst = launcherSeen
+		}
+		s.Paths = append(s.Paths, path)
+	}
+
+	if !s.Builtin && len(s.Paths) == 0 {
+		return nil
+	}
+	return s
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir() && info.Mode().Perm()&0111 != 0
+}
@@ -0,0 +1,142 @@
+package launcher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ShellInitShells are the shells 'aka shell-init' can emit code for
+var ShellInitShells = []string{"bash", "zsh", "fish"}
+
+// ShellInit renders code that defines every launcher as a shell function.
+// Functions run inside the user's shell, so directory launchers and commands
+// such as 'cd' or 'source' affect it, and no PATH entry is needed.
+func ShellInit(shell string) (string, error) {
+	var fish bool
+	switch shell {
+	case "bash", "zsh":
+	case "fish":
+		fish = true
+	default:
+		return "", fmt.Errorf("unsupported shell '%s' (use %s)", shell, strings.Join(ShellInitShells, ", "))
+	}
+
+	launchers, err := List()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# aka shell integration for %s (profile %s)\n", shell, ActiveProfile())
+	for _, l := range launchers {
+		if !ValidName(l.Name) {
+			continue
+		}
+		if fish {
+			b.WriteString(fishFunction(l))
+		} else {
+			b.WriteString(posixFunction(l))
+		}
+		if l.Metadata == nil {
+			continue
+		}
+		if l.Metadata.Type == TypeActions {
+			b.WriteString(actionCompletion(shell, l.Name, ActionNames(l.Metadata)))
+			for _, alias := range l.Metadata.Aliases {
+				b.WriteString(actionCompletion(shell, alias, ActionNames(l.Metadata)))
+			}
+		}
+		for _, alias := range l.Metadata.Aliases {
+			if fish {
+				fmt.Fprintf(&b, "function %s\n%s $argv\nend\n", alias, l.Name)
+			} else {
+				fmt.Fprintf(&b, "unalias %s 2>/dev/null\n%s() {\n%s \"$@\"\n}\n", alias, alias, l.Name)
+			}
+		}
+	}
+	return b.String(), nil
+}
+
+// posixFunction renders a bash/zsh function for a launcher. Commands without
+// environment variables run in the current shell; everything else runs in a
+// subshell so exports do not leak.
+func posixFunction(l LauncherInfo) string {
+	var body string
+	switch meta := l.Metadata; {
+	case delegatesToScript(meta):
+		body = fmt.Sprintf(`command "%s" "$@"`, launcherPath(l.Name))
+	case meta.Type == TypeDirectory:
+		body = usageLine(`"`+l.Name+`"`) + "\ncd " + shellPath(meta.Target)
+	case meta.Type == TypeActions:
+		body = usageLine(`"`+l.Name+`"`) + "\n(\n" + actionsBody(meta, l.Name) + ")"
+	case meta.Type == TypeCommand && len(meta.Env) == 0:
+		body = usageLine(`"`+l.Name+`"`) + "\n" + meta.Target
+	default:
+		body = usageLine(`"`+l.Name+`"`) + "\n(\n" + scriptBody(meta) + "\n)"
+	}
+	if !delegatesToScript(l.Metadata) {
+		// The help text lives in the script
+		body = fmt.Sprintf("if [ \"$1\" = %s ]; then\ncommand \"%s\" \"$1\"\nreturn\nfi\n", singleQuote(HelpFlag()), launcherPath(l.Name)) + body
+	}
+
+	return fmt.Sprintf("unalias %s 2>/dev/null\n%s() {\n%s\n}\n", l.Name, l.Name, body)
+}
+
+// fishFunction renders a fish function for a launcher. Directory launchers
+// change fish's own directory; the rest is POSIX shell code run through sh.
+func fishFunction(l LauncherInfo) string {
+	var body string
+	switch meta := l.Metadata; {
+	case delegatesToScript(meta):
+		body = fmt.Sprintf(`command "%s" $argv`, launcherPath(l.Name))
+	case meta.Type == TypeDirectory:
+		body = fmt.Sprintf("sh -c %s %s\ncd %s", fishQuote(usageLine(`"$0"`)), l.Name, shellPath(meta.Target))
+	default:
+		script := usageLine(`"$0"`) + "\n" + scriptBody(meta)
+		body = fmt.Sprintf("sh -c %s %s $argv", fishQuote(script), l.Name)
+	}
+	if !delegatesToScript(l.Metadata) {
+		body = fmt.Sprintf("if test \"$argv[1]\" = %s\ncommand \"%s\" $argv[1]\nreturn\nend\n", fishQuote(HelpFlag()), launcherPath(l.Name)) + body
+	}
+
+	return fmt.Sprintf("function %s\n%s\nend\n", l.Name, body)
+}
+
+// fishQuote single-quotes s for fish, where backslashes and quotes need escaping
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// delegatesToScript reports whether a launcher's function should call its
+// script file instead of inlining the code: when there is no metadata to
+// generate from, or the code would put a saved password in the shell
+func delegatesToScript(meta *LauncherMetadata) bool {
+	if meta == nil {
+		return true
+	}
+	return meta.SSHConfig != nil && meta.SSHConfig.Password != ""
+}
+
+// scriptBody is the launcher script without its shebang and header comments
+func scriptBody(meta *LauncherMetadata) string {
+	lines := strings.Split(strings.TrimRight(generateScript(meta.Target, meta), "\n"), "\n")
+	for len(lines) > 0 && strings.HasPrefix(lines[0], "#") {
+		lines = lines[1:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// actionCompletion completes the first argument of a launcher with actions
+// with its action names
+func actionCompletion(shell, name string, actions []string) string {
+	words := strings.Join(actions, " ")
+	switch shell {
+	case "bash":
+		return fmt.Sprintf("complete -W %q %s\n", words, name)
+	case "zsh":
+		return fmt.Sprintf("(( $+functions[compdef] )) && compdef \"_arguments '1:action:(%s)'\" %s\n", words, name)
+	default:
+		return fmt.Sprintf("complete -c %s -f -n 'test (count (commandline -opc)) -eq 1' -a %q\n", name, words)
+	}
+}
@@ -0,0 +1,447 @@
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ShellDefinition is an alias or function found in a shell rc file
+type ShellDefinition struct {
+	Name      string
+	Kind      string // "alias" or "function"
+	Fish      bool   // defined in fish syntax
+	Body      string
+	File      string
+	StartLine int // 1-based, inclusive
+	EndLine   int
+	// StateDependent lists why the definition cannot work as a standalone
+	// script, such as changing directory or exporting variables
+	StateDependent []string
+}
+
+// Command returns a shell command that behaves like the definition when run
+// from a launcher script, forwarding the launcher's arguments
+func (d ShellDefinition) Command() string {
+	if d.Fish {
+		// fish syntax only runs under fish, which passes extra arguments as $argv
+		script := d.Body + " $argv"
+		if d.Kind == "function" {
+			script = fmt.Sprintf("function %s\n%s\nend\n%s $argv", d.Name, d.Body, d.Name)
+		}
+		return "fish -c '" + strings.ReplaceAll(script, "'", `'\''`) + `' "$@"`
+	}
+	if d.Kind == "alias" {
+		return d.Body + ` "$@"`
+	}
+	return fmt.Sprintf("%s() {\n%s\n}\n%s \"$@\"", d.Name, d.Body, d.Name)
+}
+
+// DefaultRCFiles returns the rc files that exist for bash, zsh and fish
+func DefaultRCFiles() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	var files []string
+	for _, f := range []string{".bashrc", ".bash_aliases", ".bash_profile", ".zshrc", ".config/fish/config.fish"} {
+		path := filepath.Join(home, f)
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// ParseRCFile extracts aliases and functions from a bash, zsh or fish rc file
+func ParseRCFile(path string) ([]ShellDefinition, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []ShellDefinition
+	if strings.HasSuffix(path, ".fish") {
+		defs = parseFish(string(data))
+	} else {
+		defs = parsePOSIX(string(data))
+	}
+
+	for i := range defs {
+		defs[i].File = path
+		defs[i].Fish = strings.HasSuffix(path, ".fish")
+		defs[i].StateDependent = stateDependencies(defs[i].Body)
+	}
+	return defs, nil
+}
+
+var (
+	posixFuncPattern = regexp.MustCompile(`^\s*(?:function\s+([A-Za-z0-9_.:-]+)\s*(?:\(\s*\))?|([A-Za-z0-9_.:-]+)\s*\(\s*\))\s*\{?`)
+	fishFuncPattern  = regexp.MustCompile(`^\s*function\s+([A-Za-z0-9_.:-]+)`)
+)
+
+func parsePOSIX(content string) []ShellDefinition {
+	lines := strings.Split(content, "\n")
+	var defs []ShellDefinition
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "alias ") {
+			for _, def := range parseAliasLine(strings.TrimPrefix(trimmed, "alias ")) {
+				def.StartLine, def.EndLine = i+1, i+1
+				defs = append(defs, def)
+			}
+			continue
+		}
+
+		m := posixFuncPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := m[1]
+		if name == "" {
+			name = m[2]
+		}
+
+		end, body, ok := collectBraceBody(lines, i)
+		if !ok {
+			continue
+		}
+		defs = append(defs, ShellDefinition{
+			Name:      name,
+			Kind:      "function",
+			Body:      body,
+			StartLine: i + 1,
+			EndLine:   end + 1,
+		})
+		i = end
+	}
+
+	return defs
+}
+
+// collectBraceBody returns the last line of the function starting at start
+// and the text between its outer braces
+func collectBraceBody(lines []string, start int) (int, string, bool) {
+	depth := 0
+	opened := false
+	var body strings.Builder
+
+	for i := start; i < len(lines); i++ {
+		var quote byte
+		escaped := false
+		line := lines[i]
+		segmentStart := 0
+
+		for j := 0; j < len(line); j++ {
+			c := line[j]
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\' && quote != '\'':
+				escaped = true
+			case quote != 0:
+				if c == quote {
+					quote = 0
+				}
+			case c == '\'' || c == '"':
+				quote = c
+			case c == '#' && (j == 0 || line[j-1] == ' ' || line[j-1] == '\t'):
+				j = len(line) // rest of the line is a comment
+			case c == '{':
+				depth++
+				if !opened {
+					opened = true
+					segmentStart = j + 1
+				}
+			case c == '}':
+				depth--
+				if opened && depth == 0 {
+					body.WriteString(line[segmentStart:j])
+					return i, strings.TrimSpace(dedent(body.String())), true
+				}
+			}
+		}
+
+		if opened {
+			body.WriteString(line[segmentStart:])
+			body.WriteString("\n")
+		}
+	}
+
+	return 0, "", false
+}
+
+// parseAliasLine parses the arguments of one alias command, which may define
+// several aliases: alias ll='ls -l' la="ls -a"
+func parseAliasLine(args string) []ShellDefinition {
+	var defs []ShellDefinition
+	for _, word := range shellWords(args) {
+		if strings.HasPrefix(word, "-") {
+			continue // zsh flags such as -g
+		}
+		name, value, ok := strings.Cut(word, "=")
+		if !ok || name == "" {
+			continue
+		}
+		defs = append(defs, ShellDefinition{Name: name, Kind: "alias", Body: value})
+	}
+	return defs
+}
+
+func parseFish(content string) []ShellDefinition {
+	lines := strings.Split(content, "\n")
+	var defs []ShellDefinition
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "alias ") || strings.HasPrefix(trimmed, "abbr ") {
+			words := shellWords(trimmed)
+			var args []string
+			for _, w := range words[1:] {
+				if !strings.HasPrefix(w, "-") {
+					args = append(args, w)
+				}
+			}
+			var def ShellDefinition
+			switch {
+			case len(args) == 1 && strings.Contains(args[0], "="):
+				name, value, _ := strings.Cut(args[0], "=")
+				def = ShellDefinition{Name: name, Body: value}
+			case len(args) >= 2:
+				def = ShellDefinition{Name: args[0], Body: strings.Join(args[1:], " ")}
+			default:
+				continue
+			}
+			def.Kind = "alias"
+			def.StartLine, def.EndLine = i+1, i+1
+			defs = append(defs, def)
+			continue
+		}
+
+		m := fishFuncPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+
+		depth := 0
+		var body []string
+		end := -1
+		for j := i; j < len(lines); j++ {
+			words := strings.Fields(strings.TrimSpace(lines[j]))
+			if len(words) > 0 {
+				switch words[0] {
+				case "function", "if", "for", "while", "switch", "begin":
+					depth++
+				case "end":
+					depth--
+				}
+			}
+			if depth == 0 {
+				end = j
+				break
+			}
+			if j > i {
+				body = append(body, lines[j])
+			}
+		}
+		if end < 0 {
+			continue
+		}
+
+		defs = append(defs, ShellDefinition{
+			Name:      m[1],
+			Kind:      "function",
+			Body:      strings.TrimSpace(dedent(strings.Join(body, "\n"))),
+			StartLine: i + 1,
+			EndLine:   end + 1,
+		})
+		i = end
+	}
+
+	return defs
+}
+
+// shellWords splits s into words the way a POSIX shell would, handling
+// single quotes, double quotes and backslash escapes
+func shellWords(s string) []string {
+	var words []string
+	var current strings.Builder
+	inWord := false
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteByte(c)
+			}
+		case quote == '"':
+			switch {
+			case c == '"':
+				quote = 0
+			case c == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0:
+				i++
+				current.WriteByte(s[i])
+			default:
+				current.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			current.WriteByte(s[i])
+			inWord = true
+		case c == '#' && !inWord:
+			i = len(s)
+		case c == ' ' || c == '\t' || c == ';':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+	return words
+}
+
+// stateCommands change the calling shell and do nothing useful in a subprocess
+var stateCommands = map[string]string{
+	"cd":         "changes directory",
+	"pushd":      "changes directory",
+	"popd":       "changes directory",
+	"export":     "exports variables",
+	"set":        "changes shell options or variables",
+	"setenv":     "exports variables",
+	"unset":      "unsets variables",
+	"source":     "sources a file",
+	".":          "sources a file",
+	"alias":      "defines aliases",
+	"unalias":    "removes aliases",
+	"shopt":      "changes shell options",
+	"setopt":     "changes shell options",
+	"activate":   "activates an environment",
+	"deactivate": "deactivates an environment",
+	"hash":       "changes the command hash table",
+}
+
+var (
+	assignmentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+	// commandSeparators split a body into simple commands
+	commandSeparators = regexp.MustCompile(`\n|;|&&|\|\||\||\{|\}|\(|\)|\bthen\b|\bdo\b|\belse\b`)
+)
+
+// stateDependencies reports why a definition body relies on running inside
+// the interactive shell
+func stateDependencies(body string) []string {
+	seen := make(map[string]bool)
+	var reasons []string
+
+	for _, segment := range commandSeparators.Split(body, -1) {
+		words := strings.Fields(segment)
+		if len(words) == 0 {
+			continue
+		}
+
+		first := words[0]
+		reason, ok := stateCommands[first]
+		if first == "conda" || first == "nvm" || first == "pyenv" {
+			if len(words) > 1 && (words[1] == "activate" || words[1] == "deactivate" || words[1] == "use" || words[1] == "shell") {
+				reason, ok = "switches the environment of the current shell", true
+			}
+		}
+		if !ok && assignmentPattern.MatchString(first) && len(words) == 1 {
+			reason, ok = "sets shell variables", true
+		}
+		if ok {
+			msg := fmt.Sprintf("%s (%s)", reason, first)
+			if !seen[msg] {
+				seen[msg] = true
+				reasons = append(reasons, msg)
+			}
+		}
+	}
+
+	return reasons
+}
+
+// dedent removes the indentation shared by all non-empty lines
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	prefix := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if prefix < 0 || indent < prefix {
+			prefix = indent
+		}
+	}
+	if prefix <= 0 {
+		return s
+	}
+	for i, line := range lines {
+		if len(line) >= prefix {
+			lines[i] = line[prefix:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CommentOutDefinitions comments out the given definitions in their rc file,
+// keeping a backup of the original next to it
+func CommentOutDefinitions(path string, defs []ShellDefinition) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	backup := path + ".aka.bak"
+	if err := os.WriteFile(backup, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for _, def := range defs {
+		if def.File != path {
+			continue
+		}
+		for n := def.StartLine; n <= def.EndLine && n <= len(lines); n++ {
+			if !strings.HasPrefix(lines[n-1], "# [aka] ") {
+				lines[n-1] = "# [aka] " + lines[n-1]
+			}
+		}
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), info.Mode().Perm()); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
@@ -0,0 +1,191 @@
+package launcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecVersion is the format version of declarative launcher files (aka.yaml)
+const SpecVersion = 1
+
+// Spec is a declarative description of a set of launchers
+type Spec struct {
+	Version   int           `yaml:"version"`
+	Launchers MetadataStore `yaml:"launchers"`
+}
+
+// LoadSpec reads and validates a declarative launcher file
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spec Spec
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if spec.Version == 0 {
+		spec.Version = SpecVersion
+	}
+	if spec.Version > SpecVersion {
+		return nil, fmt.Errorf("%s uses format version %d, newer than this version of aka supports (%d)", path, spec.Version, SpecVersion)
+	}
+	if spec.Launchers == nil {
+		spec.Launchers = make(MetadataStore)
+	}
+
+	for name, meta := range spec.Launchers {
+		if meta == nil {
+			return nil, fmt.Errorf("%s: definition is empty", name)
+		}
+		Normalize(meta)
+		if err := Validate(name, meta); err != nil {
+			return nil, err
+		}
+	}
+
+	return &spec, nil
+}
+
+// ExportSpec renders the store as a declarative YAML file, without secrets
+func ExportSpec(store MetadataStore) ([]byte, error) {
+	spec := Spec{Version: SpecVersion, Launchers: make(MetadataStore, len(store))}
+	for name, meta := range store {
+		public := publicMetadata(meta)
+		Normalize(public)
+		spec.Launchers[name] = public
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(spec); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ChangeAction is what applying a spec does to one launcher
+type ChangeAction string
+
+const (
+	ActionCreate    ChangeAction = "create"
+	ActionUpdate    ChangeAction = "update"
+	ActionDelete    ChangeAction = "delete"
+	ActionUnchanged ChangeAction = "unchanged"
+)
+
+// Change is one planned step of an apply
+type Change struct {
+	Action ChangeAction
+	Name   string
+	Before *LauncherMetadata
+	After  *LauncherMetadata
+}
+
+// Fields lists the individual field changes of an update
+func (c Change) Fields() []string {
+	return FieldChanges(c.Before, c.After)
+}
+
+// Apply performs the change
+func (c Change) Apply() error {
+	switch c.Action {
+	case ActionCreate, ActionUpdate:
+		return Create(c.Name, c.After)
+	case ActionDelete:
+		if !Exists(c.Name) {
+			return DeleteMetadata(c.Name)
+		}
+		return Remove(c.Name)
+	default:
+		return nil
+	}
+}
+
+// PlanApply compares the spec with the current launchers. Launchers missing
+// from the spec are deleted only when prune is set.
+func PlanApply(spec *Spec, prune bool) ([]Change, error) {
+	store, err := LoadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for name, desired := range spec.Launchers {
+		current, ok := store[name]
+		if !ok || current == nil {
+			if Exists(name) && !IsManaged(name) {
+				return nil, fmt.Errorf("'%s' is %w and would be overwritten", name, ErrNotManaged)
+			}
+			changes = append(changes, Change{Action: ActionCreate, Name: name, After: desired})
+			continue
+		}
+
+		// Secrets never appear in the spec; keep the ones stored locally
+		after := withLocalSecrets(desired, current)
+
+		action := ActionUnchanged
+		if !SameDefinition(current, after) || !Exists(name) {
+			action = ActionUpdate
+		}
+		changes = append(changes, Change{Action: action, Name: name, Before: current, After: after})
+	}
+
+	if prune {
+		for name, current := range store {
+			if _, ok := spec.Launchers[name]; !ok {
+				changes = append(changes, Change{Action: ActionDelete, Name: name, Before: current})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(a, b int) bool { return changes[a].Name < changes[b].Name })
+	return changes, nil
+}
+
+// SameDefinition reports whether two definitions generate the same launcher,
+// ignoring secrets and defaults that 'aka add' writes out explicitly
+func SameDefinition(a, b *LauncherMetadata) bool {
+	return definitionKey(a) == definitionKey(b)
+}
+
+func definitionKey(meta *LauncherMetadata) string {
+	if meta == nil {
+		return ""
+	}
+	public := publicMetadata(meta)
+	public.Targets = append([]string(nil), meta.Targets...)
+	public.Tags = append([]string(nil), meta.Tags...)
+	public.Aliases = append([]string(nil), meta.Aliases...)
+	public.Actions = copyMap(meta.Actions)
+	public.CreatedAt = time.Time{}
+	Normalize(public)
+	data, _ := json.Marshal(public)
+	return string(data)
+}
+
+// withLocalSecrets copies secrets from the stored definition into a desired one
+func withLocalSecrets(desired, current *LauncherMetadata) *LauncherMetadata {
+	if current.SSHConfig == nil || current.SSHConfig.Password == "" || desired.Type != TypeSSH {
+		return desired
+	}
+	merged := *desired
+	ssh := SSHConfig{}
+	if desired.SSHConfig != nil {
+		ssh = *desired.SSHConfig
+	}
+	ssh.Password = current.SSHConfig.Password
+	merged.SSHConfig = &ssh
+	return &merged
+}
@@ -0,0 +1,92 @@
+package launcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// GeneratorVersion is bumped whenever generated scripts change shape, so
+// existing launchers can be detected as outdated and rebuilt
+const GeneratorVersion = 5
+
+// ScriptStamp is the generator version and content hash recorded on the marker line
+type ScriptStamp struct {
+	Generator int
+	Hash      string
+}
+
+// stampScript replaces the bare marker line with one carrying the generator
+// version and a hash of the rest of the script
+func stampScript(script string) string {
+	stamp := fmt.Sprintf("%s generator=%d hash=%s", ManagedMarker, GeneratorVersion, contentHash(script))
+	return strings.Replace(script, ManagedMarker+"\n", stamp+"\n", 1)
+}
+
+// contentHash hashes a script with its marker line removed, so the stamp
+// does not depend on itself
+func contentHash(script string) string {
+	var kept []string
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(line, ManagedMarker) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(kept, "\n")))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// ReadStamp parses the stamp from a script. Scripts from before stamping
+// report generator version 1 and no hash.
+func ReadStamp(script string) (ScriptStamp, bool) {
+	for i, line := range strings.Split(script, "\n") {
+		if i >= markerScanLines {
+			break
+		}
+		if !strings.HasPrefix(line, ManagedMarker) {
+			continue
+		}
+
+		stamp := ScriptStamp{Generator: 1}
+		for _, field := range strings.Fields(strings.TrimPrefix(line, ManagedMarker)) {
+			key, value, _ := strings.Cut(field, "=")
+			switch key {
+			case "generator":
+				stamp.Generator, _ = strconv.Atoi(value)
+			case "hash":
+				stamp.Hash = value
+			}
+		}
+		return stamp, true
+	}
+
+	if strings.Contains(script, legacyMarker) {
+		return ScriptStamp{Generator: 1}, true
+	}
+	return ScriptStamp{}, false
+}
+
+// IsHandEdited reports whether a stamped script was changed after aka wrote it
+func IsHandEdited(script string) bool {
+	stamp, ok := ReadStamp(script)
+	if !ok || stamp.Hash == "" {
+		return false
+	}
+	return stamp.Hash != contentHash(script)
+}
+
+// ScriptIsHandEdited reports whether a launcher's script was changed after aka wrote it
+func ScriptIsHandEdited(name string) (bool, error) {
+	data, err := os.ReadFile(launcherPath(name))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return IsHandEdited(string(data)), nil
+}
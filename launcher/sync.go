@@ -0,0 +1,527 @@
+package launcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// syncLaunchersDir is the directory inside the sync repository holding one
+// YAML file per launcher
+const syncLaunchersDir = "launchers"
+
+// ErrSyncNotConfigured is returned when 'aka sync init' has not been run
+var ErrSyncNotConfigured = errors.New("sync is not set up; run 'aka sync init <repo>' first")
+
+// SyncState is what aka remembers between syncs
+type SyncState struct {
+	Repo   string `json:"repo"`
+	Branch string `json:"branch"`
+	// Base holds each launcher's shared definition as of the last successful
+	// sync; it is the common ancestor for three-way merges
+	Base MetadataStore `json:"base,omitempty"`
+}
+
+// SyncOverride holds machine-specific settings that are applied on top of a
+// synced definition and never pushed
+type SyncOverride struct {
+	Exclude   bool              `yaml:"exclude,omitempty"`
+	Env       map[string]string `yaml:"env,omitempty"`
+	SSHConfig *SSHConfig        `yaml:"ssh_config,omitempty"`
+}
+
+// SyncOutcome is what a sync did (or would do) to one launcher
+type SyncOutcome string
+
+const (
+	SyncPushed     SyncOutcome = "pushed"
+	SyncPulled     SyncOutcome = "pulled"
+	SyncDeleted    SyncOutcome = "deleted"
+	SyncRemoved    SyncOutcome = "removed" // deleted upstream, removed locally
+	SyncConflict   SyncOutcome = "conflict"
+	SyncUnchanged  SyncOutcome = "unchanged"
+	SyncSkipped    SyncOutcome = "skipped" // excluded on this machine
+	SyncLocalOnly  SyncOutcome = "local"   // conflict resolved in favor of this machine
+	SyncRemoteOnly SyncOutcome = "remote"  // conflict resolved in favor of the repository
+)
+
+// SyncResult describes one launcher after a sync
+type SyncResult struct {
+	Name    string
+	Outcome SyncOutcome
+}
+
+// ConflictStrategy decides how launchers changed on both sides are merged
+type ConflictStrategy string
+
+const (
+	ConflictReport ConflictStrategy = ""
+	ConflictOurs   ConflictStrategy = "ours"
+	ConflictTheirs ConflictStrategy = "theirs"
+)
+
+func syncStatePath() string {
+	return filepath.Join(GetConfigDir(), "sync.json")
+}
+
+func syncOverridesPath() string {
+	return filepath.Join(GetConfigDir(), "overrides.yaml")
+}
+
+func syncWorktree() string {
+	return filepath.Join(GetStateDir(), "sync")
+}
+
+// LoadSyncState returns the saved sync configuration
+func LoadSyncState() (*SyncState, error) {
+	data, err := os.ReadFile(syncStatePath())
+	if os.IsNotExist(err) {
+		return nil, ErrSyncNotConfigured
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	var state SyncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state: %w", err)
+	}
+	if state.Base == nil {
+		state.Base = make(MetadataStore)
+	}
+	return &state, nil
+}
+
+func saveSyncState(state *SyncState) error {
+	if err := os.MkdirAll(GetConfigDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(syncStatePath(), data, 0600)
+}
+
+// LoadSyncOverrides returns the machine-specific overrides keyed by launcher name
+func LoadSyncOverrides() (map[string]*SyncOverride, error) {
+	overrides := make(map[string]*SyncOverride)
+
+	data, err := os.ReadFile(syncOverridesPath())
+	if os.IsNotExist(err) {
+		return overrides, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overrides: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// SaveSyncOverrides writes the machine-specific overrides
+func SaveSyncOverrides(overrides map[string]*SyncOverride) error {
+	for name, o := range overrides {
+		if o == nil || (!o.Exclude && len(o.Env) == 0 && o.SSHConfig == nil) {
+			delete(overrides, name)
+		}
+	}
+	if err := os.MkdirAll(GetConfigDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := yaml.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(syncOverridesPath(), data, 0644)
+}
+
+// InitSync clones repo (a path or URL) as the sync repository
+func InitSync(repo string) (*SyncState, error) {
+	if abs, err := filepath.Abs(repo); err == nil {
+		if _, err := os.Stat(abs); err == nil {
+			repo = abs
+		}
+	}
+
+	worktree := syncWorktree()
+	if err := os.RemoveAll(worktree); err != nil {
+		return nil, fmt.Errorf("failed to clear old sync checkout: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(worktree), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if _, err := runGit("", "clone", "--quiet", repo, worktree); err != nil {
+		return nil, err
+	}
+
+	branch, err := runGit(worktree, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	state := &SyncState{Repo: repo, Branch: branch, Base: make(MetadataStore)}
+	if err := saveSyncState(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Sync merges local launchers with the sync repository, pushes the result
+// and regenerates local scripts for anything that changed upstream.
+// With dryRun nothing is committed, pushed or changed locally.
+func Sync(strategy ConflictStrategy, dryRun bool) ([]SyncResult, error) {
+	state, err := LoadSyncState()
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := LoadSyncOverrides()
+	if err != nil {
+		return nil, err
+	}
+	store, err := LoadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	worktree := syncWorktree()
+	if _, err := os.Stat(filepath.Join(worktree, ".git")); err != nil {
+		return nil, fmt.Errorf("sync checkout is missing; run 'aka sync init %s' again", state.Repo)
+	}
+
+	if _, err := runGit(worktree, "fetch", "--quiet", "origin"); err != nil {
+		return nil, err
+	}
+	remoteRef := "origin/" + state.Branch
+	if _, err := runGit(worktree, "rev-parse", "--verify", "--quiet", remoteRef); err == nil {
+		if _, err := runGit(worktree, "reset", "--quiet", "--hard", remoteRef); err != nil {
+			return nil, err
+		}
+	}
+
+	remote, err := readSyncDefinitions(worktree)
+	if err != nil {
+		return nil, err
+	}
+
+	local := make(MetadataStore)
+	for name, meta := range store {
+		if o := overrides[name]; o != nil && o.Exclude {
+			continue
+		}
+		local[name] = sharedDefinition(meta, overrides[name], state.Base[name])
+	}
+
+	names := make(map[string]bool)
+	for _, m := range []MetadataStore{state.Base, remote, local} {
+		for name := range m {
+			names[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var results []SyncResult
+	repoChanged := false
+	type localChange struct {
+		name string
+		meta *LauncherMetadata // nil deletes
+	}
+	var localChanges []localChange
+	newBase := make(MetadataStore)
+
+	for _, name := range sorted {
+		if o := overrides[name]; o != nil && o.Exclude {
+			results = append(results, SyncResult{name, SyncSkipped})
+			if b := state.Base[name]; b != nil {
+				newBase[name] = b
+			}
+			continue
+		}
+
+		b, r, l := state.Base[name], remote[name], local[name]
+		var merged *LauncherMetadata
+		outcome := SyncUnchanged
+
+		switch {
+		case sameOrBothNil(l, r):
+			merged = r
+		case sameOrBothNil(l, b):
+			merged = r
+			outcome = SyncPulled
+			if r == nil {
+				outcome = SyncRemoved
+			}
+		case sameOrBothNil(r, b):
+			merged = l
+			outcome = SyncPushed
+			if l == nil {
+				outcome = SyncDeleted
+			}
+		case strategy == ConflictOurs:
+			merged = l
+			outcome = SyncLocalOnly
+		case strategy == ConflictTheirs:
+			merged = r
+			outcome = SyncRemoteOnly
+		default:
+			results = append(results, SyncResult{name, SyncConflict})
+			if b != nil {
+				newBase[name] = b
+			}
+			continue
+		}
+
+		results = append(results, SyncResult{name, outcome})
+		if merged != nil {
+			newBase[name] = merged
+		}
+
+		if !sameOrBothNil(merged, r) {
+			repoChanged = true
+			if !dryRun {
+				if err := writeSyncDefinition(worktree, name, merged); err != nil {
+					return nil, err
+				}
+			}
+		}
+		// Compare what this machine should run, overrides included, with what it has
+		if merged == nil {
+			if store[name] != nil {
+				localChanges = append(localChanges, localChange{name, nil})
+			}
+		} else if current := store[name]; current == nil || !SameDefinition(current, applyOverride(merged, overrides[name])) {
+			localChanges = append(localChanges, localChange{name, merged})
+		}
+	}
+
+	if dryRun {
+		return results, nil
+	}
+
+	if repoChanged {
+		if _, err := runGit(worktree, "add", "--all", syncLaunchersDir); err != nil {
+			return nil, err
+		}
+		host, _ := os.Hostname()
+		if _, err := runGit(worktree, gitIdentityArgs(worktree, "commit", "--quiet", "-m", "aka sync from "+host)...); err != nil {
+			return nil, err
+		}
+		if _, err := runGit(worktree, "push", "--quiet", "origin", "HEAD:"+state.Branch); err != nil {
+			return nil, fmt.Errorf("push failed (someone may have synced at the same time; run 'aka sync' again): %w", err)
+		}
+	}
+
+	for _, change := range localChanges {
+		if change.meta == nil {
+			if Exists(change.name) {
+				if err := Remove(change.name); err != nil {
+					return nil, fmt.Errorf("failed to remove '%s': %w", change.name, err)
+				}
+			} else if err := DeleteMetadata(change.name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		meta := applyOverride(change.meta, overrides[change.name])
+		if current := store[change.name]; current != nil {
+			meta = withLocalSecrets(meta, current)
+		}
+		if err := Create(change.name, meta); err != nil {
+			return nil, fmt.Errorf("failed to update '%s': %w", change.name, err)
+		}
+	}
+
+	state.Base = newBase
+	if err := saveSyncState(state); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// sharedDefinition strips secrets and machine-specific overrides from a local
+// definition. Overridden fields fall back to the last synced value.
+func sharedDefinition(meta *LauncherMetadata, o *SyncOverride, base *LauncherMetadata) *LauncherMetadata {
+	shared := publicMetadata(meta)
+	shared.Targets = append([]string(nil), meta.Targets...)
+	if o == nil {
+		Normalize(shared)
+		return shared
+	}
+
+	if len(o.Env) > 0 {
+		env := make(map[string]string)
+		for k, v := range meta.Env {
+			env[k] = v
+		}
+		for k := range o.Env {
+			delete(env, k)
+			if base != nil {
+				if v, ok := base.Env[k]; ok {
+					env[k] = v
+				}
+			}
+		}
+		shared.Env = env
+	}
+
+	if o.SSHConfig != nil && shared.SSHConfig != nil {
+		var baseSSH SSHConfig
+		if base != nil && base.SSHConfig != nil {
+			baseSSH = *base.SSHConfig
+		}
+		if o.SSHConfig.Port != 0 {
+			shared.SSHConfig.Port = baseSSH.Port
+		}
+		if o.SSHConfig.KeyFile != "" {
+			shared.SSHConfig.KeyFile = baseSSH.KeyFile
+		}
+	}
+
+	Normalize(shared)
+	return shared
+}
+
+// applyOverride layers this machine's overrides on a shared definition
+func applyOverride(shared *LauncherMetadata, o *SyncOverride) *LauncherMetadata {
+	meta := *shared
+	meta.Targets = append([]string(nil), shared.Targets...)
+	if o == nil {
+		return &meta
+	}
+
+	if len(o.Env) > 0 {
+		env := make(map[string]string)
+		for k, v := range shared.Env {
+			env[k] = v
+		}
+		for k, v := range o.Env {
+			env[k] = v
+		}
+		meta.Env = env
+	}
+
+	if o.SSHConfig != nil && meta.Type == TypeSSH {
+		ssh := SSHConfig{}
+		if shared.SSHConfig != nil {
+			ssh = *shared.SSHConfig
+		}
+		if o.SSHConfig.Port != 0 {
+			ssh.Port = o.SSHConfig.Port
+		}
+		if o.SSHConfig.KeyFile != "" {
+			ssh.KeyFile = o.SSHConfig.KeyFile
+		}
+		meta.SSHConfig = &ssh
+	}
+
+	return &meta
+}
+
+func sameOrBothNil(a, b *LauncherMetadata) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return SameDefinition(a, b)
+}
+
+// readSyncDefinitions loads every launcher file from the sync checkout
+func readSyncDefinitions(worktree string) (MetadataStore, error) {
+	defs := make(MetadataStore)
+
+	entries, err := os.ReadDir(filepath.Join(worktree, syncLaunchersDir))
+	if os.IsNotExist(err) {
+		return defs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync checkout: %w", err)
+	}
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".yaml")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(worktree, syncLaunchersDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var meta LauncherMetadata
+		if err := yaml.Unmarshal(data, &meta); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", entry.Name(), err)
+		}
+		Normalize(&meta)
+		if err := Validate(name, &meta); err != nil {
+			return nil, fmt.Errorf("invalid launcher in sync repository: %w", err)
+		}
+		defs[name] = &meta
+	}
+	return defs, nil
+}
+
+// writeSyncDefinition writes (or, for nil, deletes) one launcher file
+func writeSyncDefinition(worktree, name string, meta *LauncherMetadata) error {
+	dir := filepath.Join(worktree, syncLaunchersDir)
+	path := filepath.Join(dir, name+".yaml")
+
+	if meta == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(publicMetadata(meta))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// gitIdentityArgs supplies a fallback author when git has none configured,
+// so syncing works on freshly provisioned machines
+func gitIdentityArgs(worktree string, args ...string) []string {
+	if email, err := runGit(worktree, "config", "user.email"); err == nil && email != "" {
+		return args
+	}
+	host, _ := os.Hostname()
+	return append([]string{"-c", "user.name=aka", "-c", "user.email=aka@" + host}, args...)
+}
+
+// runGit runs git in dir and returns its trimmed stdout
+func runGit(dir string, args ...string) (string, error) {
+	subcommand := args[0]
+	for i := 0; i < len(args)-1 && args[i] == "-c"; i += 2 {
+		subcommand = args[i+2]
+	}
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", subcommand, msg)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
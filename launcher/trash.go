@@ -0,0 +1,161 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrashItem is a removed launcher kept so it can be restored
+type TrashItem struct {
+	// ID is the history entry that recorded the removal
+	ID        int               `json:"id"`
+	Name      string            `json:"name"`
+	RemovedAt time.Time         `json:"removed_at"`
+	Metadata  *LauncherMetadata `json:"metadata,omitempty"`
+	Script    string            `json:"script"`
+}
+
+func getTrashDir() string {
+	return filepath.Join(GetStateDir(), "trash")
+}
+
+func trashPath(id int) string {
+	return filepath.Join(getTrashDir(), fmt.Sprintf("%d.json", id))
+}
+
+func writeTrash(item TrashItem) error {
+	if err := os.MkdirAll(getTrashDir(), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(trashPath(item.ID), data, 0600)
+}
+
+func findTrash(id int) (*TrashItem, error) {
+	data, err := os.ReadFile(trashPath(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("removal #%d is no longer in the trash", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var item TrashItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("trash entry #%d is damaged: %w", id, err)
+	}
+	return &item, nil
+}
+
+// ListTrash returns the removed launchers, most recently removed first
+func ListTrash() ([]TrashItem, error) {
+	files, err := os.ReadDir(getTrashDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []TrashItem
+	for _, f := range files {
+		id, err := strconv.Atoi(strings.TrimSuffix(f.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		item, err := findTrash(id)
+		if err != nil {
+			continue
+		}
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID > items[j].ID })
+	return items, nil
+}
+
+// FindTrash looks up a trash item by its ID or, given a name, the most
+// recently removed launcher with that name
+func FindTrash(ref string) (*TrashItem, error) {
+	if id, err := strconv.Atoi(strings.TrimPrefix(ref, "#")); err == nil {
+		return findTrash(id)
+	}
+
+	items, err := ListTrash()
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if item.Name == ref {
+			return &item, nil
+		}
+	}
+	return nil, fmt.Errorf("no launcher named '%s' in the trash", ref)
+}
+
+// Restore brings a launcher back from the trash, under a new name if as is set
+func Restore(item *TrashItem, as string) error {
+	if as == "" {
+		as = item.Name
+	}
+	return restore(item, as, 0)
+}
+
+func restore(item *TrashItem, name string, reverts int) error {
+	if Exists(name) {
+		return fmt.Errorf("'%s' already exists (restore it under another name)", name)
+	}
+	if item.Metadata != nil && name != item.Name {
+		// The saved script was generated for the old name
+		if err := create(name, item.Metadata, reverts); err != nil {
+			return err
+		}
+		return os.Remove(trashPath(item.ID))
+	}
+	if item.Metadata != nil {
+		if err := checkAliases(name, item.Metadata); err != nil {
+			return err
+		}
+	}
+	if err := EnsureLauncherDir(); err != nil {
+		return fmt.Errorf("failed to create launcher directory: %w", err)
+	}
+
+	path := launcherPath(name)
+	if err := os.WriteFile(path, []byte(item.Script), 0755); err != nil {
+		return fmt.Errorf("failed to write launcher file: %w", err)
+	}
+	if item.Metadata != nil {
+		if err := SetMetadata(name, item.Metadata); err != nil {
+			return rollback(fmt.Errorf("failed to save metadata: %w", err), func() error { return os.Remove(path) })
+		}
+		if err := linkAliases(name, nil, item.Metadata); err != nil {
+			return err
+		}
+	}
+
+	_ = os.Remove(trashPath(item.ID))
+	_, _ = recordHistory(HistoryEntry{Op: OpRestore, Name: name, After: item.Metadata, Reverts: reverts})
+	return nil
+}
+
+// EmptyTrash deletes every trashed launcher for good and returns how many there were
+func EmptyTrash() (int, error) {
+	items, err := ListTrash()
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range items {
+		if err := os.Remove(trashPath(item.ID)); err != nil {
+			return 0, err
+		}
+	}
+	return len(items), nil
+}
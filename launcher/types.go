@@ -1,5 +1,7 @@
 package launcher
 
+import "time"
+
 type LauncherType string
 
 const (
@@ -8,18 +10,28 @@ const (
 	TypeSSH         LauncherType = "ssh"
 	TypeCommand     LauncherType = "cmd"
 	TypeStack       LauncherType = "stack"
+	TypeDirectory   LauncherType = "dir"
+	TypeActions     LauncherType = "actions"
 )
 
 type LauncherMetadata struct {
-	Type      LauncherType      `json:"type"`
-	Target    string            `json:"target,omitempty"`  // Legacy single target
-	Targets   []string          `json:"targets,omitempty"` // For stack type
-	Env       map[string]string `json:"env,omitempty"`
-	SSHConfig *SSHConfig        `json:"ssh_config,omitempty"`
+	Type      LauncherType      `json:"type" yaml:"type,omitempty"`
+	Target    string            `json:"target,omitempty" yaml:"target,omitempty"`   // Single target (app, url, ssh, cmd, dir)
+	Targets   []string          `json:"targets,omitempty" yaml:"targets,omitempty"` // For stack type
+	Actions   map[string]string `json:"actions,omitempty" yaml:"actions,omitempty"` // Action name to target, for actions type
+	Env       map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	SSHConfig *SSHConfig        `json:"ssh_config,omitempty" yaml:"ssh_config,omitempty"`
+
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Aliases are extra names for the launcher, linked to its script
+	Aliases []string `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	// CreatedAt is local bookkeeping, so it is left out of aka.yaml and sync
+	CreatedAt time.Time `json:"created_at,omitzero" yaml:"-"`
 }
 
 type SSHConfig struct {
-	Password string `json:"password,omitempty"`
-	Port     int    `json:"port,omitempty"`
-	KeyFile  string `json:"key_file,omitempty"`
+	Password string `json:"password,omitempty" yaml:"-"` // Never leaves this machine
+	Port     int    `json:"port,omitempty" yaml:"port,omitempty"`
+	KeyFile  string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
 }
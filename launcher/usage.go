@@ -0,0 +1,158 @@
+package launcher
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Usage summarizes how often and how recently a launcher was run
+type Usage struct {
+	Count int
+	Last  time.Time
+	// Frecency weighs every run by how recent it is, so launchers used
+	// often lately rank above ones used a lot long ago
+	Frecency float64
+}
+
+// frecencyWeight is what a run at t adds to a launcher's frecency
+func frecencyWeight(t, now time.Time) float64 {
+	switch age := now.Sub(t); {
+	case age < time.Hour:
+		return 4
+	case age < 24*time.Hour:
+		return 2
+	case age < 7*24*time.Hour:
+		return 1
+	case age < 30*24*time.Hour:
+		return 0.5
+	default:
+		return 0.25
+	}
+}
+
+// getUsagePath returns the log that launcher scripts append a line to on
+// every run: "<unix time> <name>"
+func getUsagePath() string {
+	return filepath.Join(GetStateDir(), "usage.log")
+}
+
+// usageLine is the shell line that records a run of the launcher named by
+// nameExpr. Failures are ignored so a read-only state dir never breaks a launcher.
+func usageLine(nameExpr string) string {
+	return fmt.Sprintf(`{ printf '%%s %%s\n' "$(date +%%s)" %s >> "%s"; } 2>/dev/null`, nameExpr, getUsagePath())
+}
+
+// withUsage inserts the usage line after the script's header comments
+func withUsage(script string) string {
+	lines := strings.SplitAfter(script, "\n")
+	i := 0
+	for i < len(lines) && strings.HasPrefix(lines[i], "#") {
+		i++
+	}
+	line := usageLine(`"${0##*/}"`) + "\n"
+	return strings.Join(lines[:i], "") + line + strings.Join(lines[i:], "")
+}
+
+// LoadUsage reads the usage log, keyed by launcher name. A missing log is
+// not an error.
+func LoadUsage() (map[string]Usage, error) {
+	usage := make(map[string]Usage)
+
+	f, err := os.Open(getUsagePath())
+	if os.IsNotExist(err) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	now := time.Now()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		stamp, name, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		sec, err := strconv.ParseInt(stamp, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		u := usage[name]
+		u.Count++
+		at := time.Unix(sec, 0)
+		if at.After(u.Last) {
+			u.Last = at
+		}
+		u.Frecency += frecencyWeight(at, now)
+		usage[name] = u
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Runs through an alias count for the launcher it belongs to
+	store, _ := LoadMetadata()
+	for name, meta := range store {
+		if meta == nil {
+			continue
+		}
+		for _, alias := range meta.Aliases {
+			a, ok := usage[alias]
+			if !ok {
+				continue
+			}
+			u := usage[name]
+			u.Count += a.Count
+			u.Frecency += a.Frecency
+			if a.Last.After(u.Last) {
+				u.Last = a.Last
+			}
+			usage[name] = u
+			delete(usage, alias)
+		}
+	}
+	return usage, nil
+}
+
+// renameUsage moves the usage history of a launcher to a new name, or drops
+// it when newName is empty
+func renameUsage(oldName, newName string) error {
+	path := getUsagePath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var kept []string
+	changed := false
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		stamp, name, ok := strings.Cut(line, " ")
+		if ok && name == oldName {
+			changed = true
+			if newName == "" {
+				continue
+			}
+			line = stamp + " " + newName
+		}
+		kept = append(kept, line)
+	}
+	if !changed {
+		return nil
+	}
+
+	content := strings.Join(kept, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
@@ -0,0 +1,247 @@
+package launcher
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	namePattern   = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	tagPattern    = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+)
+
+// ValidName reports whether name can be used as a launcher name
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Normalize fills in what can be inferred from a hand-written definition:
+// a missing type is detected from the target(s), and fields that do not
+// apply to the type are dropped
+func Normalize(meta *LauncherMetadata) {
+	if meta.Type == "" {
+		if len(meta.Actions) > 0 {
+			meta.Type = TypeActions
+		} else if len(meta.Targets) > 1 || (meta.Target == "" && len(meta.Targets) > 0) {
+			meta.Type = TypeStack
+		} else {
+			meta.Type = DetectLauncherType(meta.Target)
+		}
+	}
+
+	if meta.Type == TypeActions {
+		meta.Target = ""
+		meta.Targets = nil
+	} else if meta.Type == TypeStack {
+		if len(meta.Targets) == 0 && meta.Target != "" {
+			meta.Targets = []string{meta.Target}
+		}
+		meta.Target = ""
+	} else {
+		if meta.Target == "" && len(meta.Targets) == 1 {
+			meta.Target = meta.Targets[0]
+		}
+		meta.Targets = nil
+	}
+
+	if meta.Type != TypeSSH {
+		meta.SSHConfig = nil
+	} else if ssh := meta.SSHConfig; ssh != nil {
+		if ssh.Port == 22 {
+			ssh.Port = 0 // the default, written out by 'aka add'
+		}
+		if *ssh == (SSHConfig{}) {
+			meta.SSHConfig = nil
+		}
+	}
+	if len(meta.Env) == 0 {
+		meta.Env = nil
+	}
+	if meta.Type != TypeActions || len(meta.Actions) == 0 {
+		meta.Actions = nil
+	}
+
+	meta.Description = strings.TrimSpace(meta.Description)
+	meta.Tags = NormalizeTags(meta.Tags)
+	meta.Aliases = NormalizeAliases(meta.Aliases)
+}
+
+// NormalizeTags lowercases, sorts and de-duplicates tags
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// HasTags reports whether meta carries every one of tags
+func HasTags(meta *LauncherMetadata, tags []string) bool {
+	if meta == nil {
+		return len(tags) == 0
+	}
+	for _, want := range NormalizeTags(tags) {
+		found := false
+		for _, tag := range meta.Tags {
+			if tag == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks a launcher definition the same way 'aka add' does
+func Validate(name string, meta *LauncherMetadata) error {
+	if !ValidName(name) {
+		return fmt.Errorf("invalid name '%s': use only alphanumeric characters, hyphens, and underscores", name)
+	}
+	if meta == nil {
+		return fmt.Errorf("%s: definition is empty", name)
+	}
+
+	switch meta.Type {
+	case TypeApplication, TypeURL, TypeSSH, TypeCommand, TypeDirectory:
+		if strings.TrimSpace(meta.Target) == "" {
+			return fmt.Errorf("%s: target is required", name)
+		}
+	case TypeStack:
+		if len(meta.Targets) == 0 {
+			return fmt.Errorf("%s: a stack needs at least one target", name)
+		}
+		for i, t := range meta.Targets {
+			if strings.TrimSpace(t) == "" {
+				return fmt.Errorf("%s: stack target %d is empty", name, i+1)
+			}
+		}
+	case TypeActions:
+		if len(meta.Actions) == 0 {
+			return fmt.Errorf("%s: a launcher with actions needs at least one action", name)
+		}
+		for action, target := range meta.Actions {
+			if !ValidName(action) || strings.HasPrefix(action, "-") || action == "help" {
+				return fmt.Errorf("%s: invalid action name '%s'", name, action)
+			}
+			if strings.TrimSpace(target) == "" {
+				return fmt.Errorf("%s: action '%s' has no target", name, action)
+			}
+		}
+	default:
+		return fmt.Errorf("%s: unknown type '%s'", name, meta.Type)
+	}
+
+	for key := range meta.Env {
+		if !envKeyPattern.MatchString(key) {
+			return fmt.Errorf("%s: invalid environment variable name '%s'", name, key)
+		}
+	}
+
+	for _, tag := range meta.Tags {
+		if !tagPattern.MatchString(tag) {
+			return fmt.Errorf("%s: invalid tag '%s': use only alphanumeric characters, dots, hyphens, and underscores", name, tag)
+		}
+	}
+
+	for _, alias := range meta.Aliases {
+		if !ValidName(alias) {
+			return fmt.Errorf("%s: invalid alias '%s': use only alphanumeric characters, hyphens, and underscores", name, alias)
+		}
+		if alias == name {
+			return fmt.Errorf("%s: a launcher cannot be its own alias", name)
+		}
+	}
+
+	if meta.SSHConfig != nil && (meta.SSHConfig.Port < 0 || meta.SSHConfig.Port > 65535) {
+		return fmt.Errorf("%s: SSH port %d is out of range", name, meta.SSHConfig.Port)
+	}
+
+	return nil
+}
+
+// FieldChanges lists the fields that differ between two definitions as
+// human-readable "field: old -> new" lines. Secrets are never shown.
+func FieldChanges(before, after *LauncherMetadata) []string {
+	a, b := flattenMetadata(before), flattenMetadata(after)
+
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var changes []string
+	for _, k := range sorted {
+		oldValue, hadOld := a[k]
+		newValue, hasNew := b[k]
+		switch {
+		case !hadOld:
+			changes = append(changes, fmt.Sprintf("%s: %q", k, newValue))
+		case !hasNew:
+			changes = append(changes, fmt.Sprintf("%s: %q -> (removed)", k, oldValue))
+		case oldValue != newValue:
+			changes = append(changes, fmt.Sprintf("%s: %q -> %q", k, oldValue, newValue))
+		}
+	}
+	return changes
+}
+
+// flattenMetadata turns a definition into dotted field paths for comparison
+func flattenMetadata(meta *LauncherMetadata) map[string]string {
+	fields := make(map[string]string)
+	if meta == nil {
+		return fields
+	}
+
+	fields["type"] = string(meta.Type)
+	if meta.Target != "" {
+		fields["target"] = meta.Target
+	}
+	if len(meta.Targets) > 0 {
+		fields["targets"] = strings.Join(meta.Targets, ", ")
+	}
+	for action, target := range meta.Actions {
+		fields["actions."+action] = target
+	}
+	for k, v := range meta.Env {
+		fields["env."+k] = v
+	}
+	if meta.Description != "" {
+		fields["description"] = meta.Description
+	}
+	if len(meta.Tags) > 0 {
+		fields["tags"] = strings.Join(meta.Tags, ", ")
+	}
+	if len(meta.Aliases) > 0 {
+		fields["aliases"] = strings.Join(meta.Aliases, ", ")
+	}
+	if ssh := meta.SSHConfig; ssh != nil {
+		if ssh.Port != 0 {
+			fields["ssh.port"] = fmt.Sprintf("%d", ssh.Port)
+		}
+		if ssh.KeyFile != "" {
+			fields["ssh.key_file"] = ssh.KeyFile
+		}
+	}
+	return fields
+}
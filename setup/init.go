@@ -2,12 +2,22 @@ package setup
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/dorochadev/aka/launcher"
 	"github.com/dorochadev/aka/ui"
 )
 
-var hasShownWelcome = false
+var (
+	hasShownWelcome = false
+	quiet           = false
+)
+
+// SetQuiet suppresses setup messages, for commands whose output is meant to
+// be consumed by a shell or another program
+func SetQuiet() {
+	quiet = true
+}
 
 // EnsureSetup performs first-run initialization
 func EnsureSetup() error {
@@ -15,13 +25,35 @@ func EnsureSetup() error {
 		return fmt.Errorf("failed to setup launcher directory: %w", err)
 	}
 
-	if !launcher.IsInPath() && !hasShownWelcome {
+	if !launcher.IsInPath() && !hasShownWelcome && !quiet {
 		showPathWarning()
 	}
 
+	autoRebuild()
+
 	return nil
 }
 
+// autoRebuild regenerates outdated launchers once after aka is upgraded to a
+// new generator version. Set AKA_NO_AUTO_REBUILD to turn it off.
+func autoRebuild() {
+	if os.Getenv("AKA_NO_AUTO_REBUILD") != "" || launcher.LastGeneratorVersion() >= launcher.GeneratorVersion {
+		return
+	}
+
+	rebuilt, err := launcher.RebuildOutdated()
+	if quiet {
+		return
+	}
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Could not update launchers to the new generator: %v", err))
+		return
+	}
+	if len(rebuilt) > 0 {
+		ui.PrintInfo(fmt.Sprintf("Updated %d launcher(s) to generator v%d", len(rebuilt), launcher.GeneratorVersion))
+	}
+}
+
 // showPathWarning displays a warning if the launcher directory is not in PATH
 func showPathWarning() {
 	hasShownWelcome = true
@@ -32,7 +64,12 @@ func showPathWarning() {
 	fmt.Println()
 	ui.PrintInfo("To use launchers directly, add this directory to your PATH:")
 	fmt.Println()
-	ui.PrintCommand(fmt.Sprintf(`echo 'export PATH="%s:$PATH"' >> ~/.zshrc`, dir))
+	if launcher.ActiveProfile() != launcher.DefaultProfile {
+		// Profile directories change with 'aka profile use', so resolve them at shell startup
+		ui.PrintCommand(`echo 'eval "$(aka profile env)"' >> ~/.zshrc`)
+	} else {
+		ui.PrintCommand(fmt.Sprintf(`echo 'export PATH="%s:$PATH"' >> ~/.zshrc`, dir))
+	}
 	ui.PrintCommand("source ~/.zshrc")
 	fmt.Println()
 }
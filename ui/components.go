@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Box drawing characters
@@ -124,6 +125,113 @@ func Table(headers []string, rows [][]string) {
 	}
 }
 
+// Diff prints a unified diff with added and removed lines highlighted
+func Diff(diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		fmt.Print("  ")
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			CurrentTheme.Label.Println(line)
+		case strings.HasPrefix(line, "@@"):
+			CurrentTheme.Accent.Println(line)
+		case strings.HasPrefix(line, "+"):
+			CurrentTheme.Success.Println(line)
+		case strings.HasPrefix(line, "-"):
+			CurrentTheme.Error.Println(line)
+		default:
+			CurrentTheme.Muted.Println(line)
+		}
+	}
+}
+
+// Script prints a shell script with line numbers and light syntax highlighting:
+// comments are muted, the command word of each line is accented and quoted
+// strings and variables stand out
+func Script(script string) {
+	lines := strings.Split(strings.TrimSuffix(script, "\n"), "\n")
+	width := len(fmt.Sprint(len(lines)))
+	for i, line := range lines {
+		CurrentTheme.Muted.Printf("  %*d  ", width, i+1)
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			CurrentTheme.Muted.Println(line)
+			continue
+		}
+		highlightShellLine(line)
+		fmt.Println()
+	}
+}
+
+// highlightShellLine prints one line of shell code in theme colors
+func highlightShellLine(line string) {
+	commandWord := true
+	for i := 0; i < len(line); {
+		c := line[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(line[i+1:], c)
+			if end < 0 {
+				end = len(line) - i - 1
+			} else {
+				end++
+			}
+			CurrentTheme.Success.Print(line[i : i+end+1])
+			i += end + 1
+			commandWord = false
+		case c == '$':
+			end := i + 1
+			for end < len(line) && (isWordByte(line[end]) || line[end] == '{' || line[end] == '}' || line[end] == '@') {
+				end++
+			}
+			CurrentTheme.Warning.Print(line[i:end])
+			i = end
+			commandWord = false
+		case c == ' ' || c == '\t':
+			fmt.Print(string(c))
+			i++
+		case strings.IndexByte("|&;(){}<>", c) >= 0:
+			CurrentTheme.Muted.Print(string(c))
+			i++
+			commandWord = c != '<' && c != '>'
+		default:
+			end := i
+			for end < len(line) && !strings.ContainsRune(" \t'\"$|&;(){}<>", rune(line[end])) {
+				end++
+			}
+			if commandWord {
+				CurrentTheme.Accent.Print(line[i:end])
+			} else {
+				CurrentTheme.Body.Print(line[i:end])
+			}
+			i = end
+			commandWord = false
+		}
+	}
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// Ago formats a time relative to now, such as "3h ago"
+func Ago(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	default:
+		return t.Local().Format("2006-01-02")
+	}
+}
+
 // sum calculates the sum of integers in a slice
 func sum(nums []int) int {
 	total := 0
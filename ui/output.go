@@ -3,8 +3,28 @@ package ui
 import (
 	"fmt"
 	"os"
+
+	"github.com/gookit/color"
 )
 
+// errorHook receives error messages instead of stderr when set
+var errorHook func(message string)
+
+// CaptureErrors hands every message PrintError would print to fn instead,
+// for commands that report errors in a machine-readable form
+func CaptureErrors(fn func(message string)) {
+	errorHook = fn
+}
+
+// UseStderr sends everything the ui and fmt print to stderr and returns the
+// original stdout, so a command can keep stdout for machine-readable data
+func UseStderr() *os.File {
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	color.SetOutput(os.Stderr)
+	return stdout
+}
+
 // PrintSuccess prints a success message
 func PrintSuccess(message string) {
 	SuccessBox(message)
@@ -12,6 +32,10 @@ func PrintSuccess(message string) {
 
 // PrintError prints an error message to stderr
 func PrintError(message string) {
+	if errorHook != nil {
+		errorHook(message)
+		return
+	}
 	fmt.Fprintf(os.Stderr, "%s", CurrentTheme.Error.Sprintf("%s %s\n", IconError, message))
 }
 
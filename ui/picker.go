@@ -0,0 +1,362 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrPickCancelled is returned when the picker is closed without a choice
+var ErrPickCancelled = errors.New("cancelled")
+
+// PickerItem is one entry of the picker
+type PickerItem struct {
+	// Name is shown first and matches count double
+	Name string
+	// Detail follows the name, muted, and is searched too
+	Detail string
+	// Preview fills the pane beside the list while the item is selected
+	Preview string
+	// Rank orders the items while the query is empty, highest first, and
+	// breaks ties between equally good matches
+	Rank float64
+}
+
+// Picker is a fuzzy finder over Items. It reads keys from In and draws a
+// Width x Height screen to Out, so it runs the same on a terminal in raw
+// mode, a pseudo-terminal or a pipe.
+type Picker struct {
+	Items  []PickerItem
+	Query  string
+	Width  int
+	Height int
+	In     io.Reader
+	Out    io.Writer
+
+	matches []int
+	cursor  int
+	offset  int
+}
+
+// pickerAction is what a batch of keys asks the picker to do
+type pickerAction int
+
+const (
+	pickerContinue pickerAction = iota
+	pickerAccept
+	pickerCancel
+)
+
+// Pick runs a picker on the controlling terminal and returns the index of
+// the chosen item. It draws on /dev/tty, so stdout stays free for the
+// caller's output.
+func Pick(items []PickerItem, query string) (int, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return -1, fmt.Errorf("the picker needs a terminal: %w", err)
+	}
+	defer tty.Close()
+
+	fd := int(tty.Fd())
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		return -1, fmt.Errorf("the picker needs a terminal: %w", err)
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return -1, err
+	}
+	defer term.Restore(fd, state)
+
+	p := &Picker{Items: items, Query: query, Width: width, Height: height, In: tty, Out: tty}
+	return p.Run()
+}
+
+// Run shows the picker until an item is chosen with Enter or the picker is
+// closed with Esc, Ctrl-C or the end of input
+func (p *Picker) Run() (int, error) {
+	// The alternate screen keeps the picker out of the scrollback
+	io.WriteString(p.Out, "\x1b[?1049h")
+	defer io.WriteString(p.Out, "\x1b[?1049l")
+
+	p.filter()
+	buf := make([]byte, 256)
+	for {
+		p.draw()
+		n, err := p.In.Read(buf)
+		switch p.handle(buf[:n]) {
+		case pickerAccept:
+			return p.matches[p.cursor], nil
+		case pickerCancel:
+			return -1, ErrPickCancelled
+		}
+		if err == io.EOF {
+			return -1, ErrPickCancelled
+		}
+		if err != nil {
+			return -1, err
+		}
+	}
+}
+
+// handle applies keys as read in one go; an escape sequence arrives whole,
+// so a lone Esc at the end of a read is the Esc key
+func (p *Picker) handle(keys []byte) pickerAction {
+	query := p.Query
+	for i := 0; i < len(keys); i++ {
+		switch c := keys[i]; c {
+		case 0x1b:
+			if i+1 == len(keys) {
+				return pickerCancel
+			}
+			if i+2 < len(keys) && (keys[i+1] == '[' || keys[i+1] == 'O') {
+				switch keys[i+2] {
+				case 'A':
+					p.move(-1)
+				case 'B':
+					p.move(1)
+				case '5':
+					p.move(-p.rows())
+				case '6':
+					p.move(p.rows())
+				}
+				i += 2
+				for i+1 < len(keys) && keys[i] >= '0' && keys[i] <= '9' && keys[i+1] == '~' {
+					i++
+				}
+			}
+		case '\r', '\n':
+			if len(p.matches) > 0 {
+				return pickerAccept
+			}
+		case 3, 7: // Ctrl-C, Ctrl-G
+			return pickerCancel
+		case 4: // Ctrl-D closes like in a shell, once there is nothing typed
+			if p.Query == "" {
+				return pickerCancel
+			}
+		case 16, 11: // Ctrl-P, Ctrl-K
+			p.move(-1)
+		case 14, 9: // Ctrl-N, Tab
+			p.move(1)
+		case 127, 8: // Backspace
+			if p.Query != "" {
+				_, size := utf8.DecodeLastRuneInString(p.Query)
+				p.Query = p.Query[:len(p.Query)-size]
+			}
+		case 21: // Ctrl-U
+			p.Query = ""
+		case 23: // Ctrl-W
+			p.Query = strings.TrimRightFunc(p.Query, unicode.IsSpace)
+			p.Query = p.Query[:strings.LastIndexFunc(p.Query, unicode.IsSpace)+1]
+		default:
+			if c < 0x20 {
+				continue
+			}
+			r, size := utf8.DecodeRune(keys[i:])
+			if r != utf8.RuneError {
+				p.Query += string(r)
+			}
+			i += size - 1
+		}
+		if p.Query != query {
+			p.filter()
+			query = p.Query
+		}
+	}
+	return pickerContinue
+}
+
+// move moves the selection by delta, stopping at either end
+func (p *Picker) move(delta int) {
+	p.cursor = max(0, min(len(p.matches)-1, p.cursor+delta))
+}
+
+// rows is how many items fit below the prompt and counter lines
+func (p *Picker) rows() int {
+	return max(1, p.Height-2)
+}
+
+// filter matches the query against every item and orders the matches
+func (p *Picker) filter() {
+	terms := strings.Fields(strings.ToLower(p.Query))
+	scores := make(map[int]int)
+	p.matches = p.matches[:0]
+	for i, item := range p.Items {
+		if score, ok := matchItem(terms, item); ok {
+			scores[i] = score
+			p.matches = append(p.matches, i)
+		}
+	}
+	sort.SliceStable(p.matches, func(a, b int) bool {
+		i, j := p.matches[a], p.matches[b]
+		if scores[i] != scores[j] {
+			return scores[i] > scores[j]
+		}
+		if p.Items[i].Rank != p.Items[j].Rank {
+			return p.Items[i].Rank > p.Items[j].Rank
+		}
+		return p.Items[i].Name < p.Items[j].Name
+	})
+	p.cursor, p.offset = 0, 0
+}
+
+// matchItem scores an item against every term of the query; each term must
+// match the name or the name and detail together
+func matchItem(terms []string, item PickerItem) (int, bool) {
+	name := []rune(strings.ToLower(item.Name))
+	all := []rune(strings.ToLower(item.Name + " " + item.Detail))
+
+	total := 0
+	for _, t := range terms {
+		term := []rune(t)
+		if score, ok := fuzzyScore(term, name); ok {
+			total += 2 * score
+			if strings.HasPrefix(string(name), t) {
+				total += 10
+			}
+			continue
+		}
+		score, ok := fuzzyScore(term, all)
+		if !ok {
+			return 0, false
+		}
+		total += score
+	}
+	return total, true
+}
+
+// fuzzyScore finds pattern in text as a subsequence and scores the best
+// placement: runs of consecutive characters and characters at the start of
+// a word count more
+func fuzzyScore(pattern, text []rune) (int, bool) {
+	if len(pattern) == 0 {
+		return 0, true
+	}
+
+	best, found := 0, false
+	for start := range text {
+		if text[start] != pattern[0] {
+			continue
+		}
+		score, k, prev := 0, 0, -2
+		for i := start; i < len(text) && k < len(pattern); i++ {
+			if text[i] != pattern[k] {
+				continue
+			}
+			score++
+			if i == prev+1 {
+				score += 5
+			}
+			if i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1]) {
+				score += 3
+			}
+			prev = i
+			k++
+		}
+		if k == len(pattern) && (!found || score > best) {
+			best, found = score, true
+		}
+	}
+	return best, found
+}
+
+// draw redraws the whole screen: prompt, counter, the visible part of the
+// list and, when there is room, the preview of the selected item
+func (p *Picker) draw() {
+	rows := p.rows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+rows {
+		p.offset = p.cursor - rows + 1
+	}
+
+	listWidth, previewWidth := p.Width, 0
+	if p.Width >= 80 {
+		listWidth = p.Width * 2 / 5
+		previewWidth = p.Width - listWidth - 3
+	}
+
+	nameWidth := 0
+	for _, i := range p.matches {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(p.Items[i].Name))
+	}
+	nameWidth = min(nameWidth, listWidth/2)
+
+	var preview []string
+	if previewWidth > 0 && len(p.matches) > 0 {
+		text := strings.ReplaceAll(p.Items[p.matches[p.cursor]].Preview, "\t", "    ")
+		preview = strings.Split(strings.TrimRight(text, "\n"), "\n")
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	line := func(s string) {
+		b.WriteString("\x1b[2K" + s + "\r\n")
+	}
+
+	line(CurrentTheme.Primary.Sprint(IconPointer+" ") + p.Query)
+	counter := fmt.Sprintf("  %d/%d ", len(p.matches), len(p.Items))
+	line(CurrentTheme.Muted.Sprint(counter) + CurrentTheme.Border.Sprint(strings.Repeat(BoxHorizontal, max(0, p.Width-len(counter)-1))))
+
+	for r := 0; r < rows; r++ {
+		var row string
+		if n := p.offset + r; n < len(p.matches) {
+			item := p.Items[p.matches[n]]
+			name := pad(clip(item.Name, nameWidth), nameWidth)
+			detail := clip(item.Detail, listWidth-nameWidth-4)
+			if n == p.cursor {
+				row = CurrentTheme.Accent.Sprint(IconPointer+" ") + CurrentTheme.Highlight.Sprint(name)
+			} else {
+				row = "  " + CurrentTheme.Body.Sprint(name)
+			}
+			row += "  " + CurrentTheme.Muted.Sprint(detail)
+			row += strings.Repeat(" ", max(0, listWidth-nameWidth-4-utf8.RuneCountInString(detail)))
+		} else {
+			row = strings.Repeat(" ", listWidth)
+		}
+		if previewWidth > 0 {
+			row += " " + CurrentTheme.Border.Sprint(BoxVertical) + " "
+			if r < len(preview) {
+				row += CurrentTheme.Secondary.Sprint(clip(preview[r], previewWidth))
+			}
+		}
+		if r == rows-1 {
+			b.WriteString("\x1b[2K" + row)
+		} else {
+			line(row)
+		}
+	}
+
+	// Leave the cursor after the query
+	fmt.Fprintf(&b, "\x1b[1;%dH", 3+utf8.RuneCountInString(p.Query))
+	io.WriteString(p.Out, b.String())
+}
+
+// clip shortens s to at most width characters
+func clip(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(s)
+	if len(runes) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// pad fills s with spaces up to width characters
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-utf8.RuneCountInString(s)))
+}